@@ -0,0 +1,176 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package callees implements the resolution of the functions that may be
+// called at a call site, using the call graph selected in the configuration.
+package callees
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/google/go-flow-levee/internal/pkg/config"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/buildssa"
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/callgraph/cha"
+	"golang.org/x/tools/go/callgraph/rta"
+	"golang.org/x/tools/go/callgraph/static"
+	"golang.org/x/tools/go/callgraph/vta"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+// ResultType resolves the functions that may be called at call sites.
+// It also holds the call graphs constructed for the analyzed package, so
+// that the analyzers requiring it do not construct them again.
+type ResultType struct {
+	// The functions that each call site may call.
+	// Calls with a static callee are not included.
+	callees map[ssa.CallInstruction][]*ssa.Function
+	graphs  *graphs
+}
+
+// Callees returns the functions that may be called at a call site.
+func (r ResultType) Callees(call ssa.CallInstruction) []*ssa.Function {
+	if callee := call.Common().StaticCallee(); callee != nil {
+		return []*ssa.Function{callee}
+	}
+	return r.callees[call]
+}
+
+// CallGraph returns the call graph of the given type for the analyzed
+// package's program. Each type of call graph is only constructed once.
+// It returns nil if the result does not come from a pass of the analyzer.
+func (r ResultType) CallGraph(t config.CallGraphType) *callgraph.Graph {
+	if r.graphs == nil {
+		return nil
+	}
+	return r.graphs.get(t)
+}
+
+// graphs holds the call graphs of a package's program, by type.
+// Analyzers requiring the callees analyzer may run concurrently,
+// so the graphs are constructed under a lock.
+type graphs struct {
+	mu       sync.Mutex
+	ssaInput *buildssa.SSA
+	built    map[config.CallGraphType]*callgraph.Graph
+}
+
+func (g *graphs) get(t config.CallGraphType) *callgraph.Graph {
+	if t == "" {
+		t = config.StaticCallGraph
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	cg, ok := g.built[t]
+	if !ok {
+		cg = newCallGraph(t, g.ssaInput)
+		g.built[t] = cg
+	}
+	return cg
+}
+
+// Analyzer reads its configuration from the file selected by the -config flag.
+var Analyzer = NewAnalyzer(nil)
+
+// NewAnalyzer returns an analyzer bound to conf. If conf is nil,
+// the analyzer reads its configuration as Analyzer does.
+func NewAnalyzer(conf *config.Config) *analysis.Analyzer {
+	return &analysis.Analyzer{
+		Name: "callees",
+		Doc: `This analyzer resolves the functions that may be called at call sites.
+
+Dynamic calls, such as calls to interface methods and to function values,
+are resolved using the call graph selected in the configuration.
+By default, only static calls are resolved.`,
+		Flags: config.NewFlagSet(conf),
+		Run: func(pass *analysis.Pass) (interface{}, error) {
+			return run(pass, conf)
+		},
+		Requires:   []*analysis.Analyzer{buildssa.Analyzer},
+		ResultType: reflect.TypeOf(new(ResultType)).Elem(),
+	}
+}
+
+func run(pass *analysis.Pass, conf *config.Config) (interface{}, error) {
+	ssaInput := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
+
+	conf, err := config.Load(conf, pass.Pkg.Path())
+	if err != nil {
+		return nil, err
+	}
+
+	result := ResultType{
+		callees: make(map[ssa.CallInstruction][]*ssa.Function),
+		graphs:  &graphs{ssaInput: ssaInput, built: make(map[config.CallGraphType]*callgraph.Graph)},
+	}
+	// Static calls need not be resolved using a call graph.
+	if conf.CallGraph == "" || conf.CallGraph == config.StaticCallGraph {
+		return result, nil
+	}
+
+	cg := result.CallGraph(conf.CallGraph)
+	if cg == nil {
+		return result, nil
+	}
+	for _, fn := range ssaInput.SrcFuncs {
+		node := cg.Nodes[fn]
+		if node == nil {
+			continue
+		}
+		for _, out := range node.Out {
+			if out.Site == nil || out.Site.Common().StaticCallee() != nil {
+				continue
+			}
+			result.callees[out.Site] = addCallee(result.callees[out.Site], out.Callee.Func)
+		}
+	}
+	// The order of a call graph's edges is not guaranteed to be stable.
+	for _, fns := range result.callees {
+		sort.Slice(fns, func(i, j int) bool { return fns[i].String() < fns[j].String() })
+	}
+	return result, nil
+}
+
+// newCallGraph constructs a call graph of the given type for the analyzed package's program.
+func newCallGraph(t config.CallGraphType, ssaInput *buildssa.SSA) *callgraph.Graph {
+	prog := ssaInput.Pkg.Prog
+	switch t {
+	case config.CHACallGraph:
+		return cha.CallGraph(prog)
+	case config.RTACallGraph:
+		// Any function in the package may be called by code that is not
+		// being analyzed, so every function is a root.
+		res := rta.Analyze(ssaInput.SrcFuncs, true)
+		if res == nil {
+			return nil
+		}
+		return res.CallGraph
+	case config.VTACallGraph:
+		return vta.CallGraph(ssautil.AllFunctions(prog), cha.CallGraph(prog))
+	}
+	return static.CallGraph(prog)
+}
+
+func addCallee(callees []*ssa.Function, fn *ssa.Function) []*ssa.Function {
+	for _, c := range callees {
+		if c == fn {
+			return callees
+		}
+	}
+	return append(callees, fn)
+}
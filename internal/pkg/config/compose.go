@@ -0,0 +1,211 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/google/go-flow-levee/internal/pkg/config/regexp"
+)
+
+// An Overlay adds settings to the configuration of the packages
+// whose path is matched by Package.
+type Overlay struct {
+	Package       StringMatcher
+	Sinks         []SinkMatcher
+	Sanitizers    []SanitizerMatcher
+	Exclude       []FuncMatcher
+	ReportMessage string
+}
+
+// this type uses the default unmarshaller and mirrors configuration key-value pairs
+type rawOverlay struct {
+	Package       *literalMatcher
+	PackageRE     *regexp.Regexp
+	Sinks         []SinkMatcher
+	Sanitizers    []SanitizerMatcher
+	Exclude       []FuncMatcher
+	ReportMessage string
+}
+
+func (o *Overlay) UnmarshalJSON(bytes []byte) error {
+	validOverlayFields := []string{"package", "packageRE", "sinks", "sanitizers", "exclude", "reportMessage"}
+	if err := validateFieldNames(&bytes, "Overlay", validOverlayFields); err != nil {
+		return err
+	}
+
+	raw := rawOverlay{}
+	if err := json.Unmarshal(bytes, &raw); err != nil {
+		return err
+	}
+
+	if raw.Package != nil && raw.PackageRE != nil {
+		return fmt.Errorf("expected only one of Package, PackageRE in config definition for an overlay")
+	}
+
+	*o = Overlay{
+		Package:       matcherFrom(raw.Package, raw.PackageRE),
+		Sinks:         raw.Sinks,
+		Sanitizers:    raw.Sanitizers,
+		Exclude:       raw.Exclude,
+		ReportMessage: raw.ReportMessage,
+	}
+	return nil
+}
+
+// ForPackage returns the configuration of the package at path, i.e. the
+// configuration with the Overlays matching the path applied in order.
+// The sinks, sanitizers and exclusions of an overlay are added to those
+// of the configuration, and its ReportMessage, if any, replaces the
+// configuration's. Sinks added by an overlay are named after it,
+// e.g. "Overlays[0].Sinks[1]".
+func (c *Config) ForPackage(path string) *Config {
+	pc := c
+	for i, o := range c.Overlays {
+		if !matchString(o.Package, path) {
+			continue
+		}
+		if pc == c {
+			copied := *c
+			pc = &copied
+		}
+		if len(o.Sinks) > 0 {
+			// Copy the sinks so that the configuration's are not modified.
+			names := make([]string, 0, len(pc.Sinks)+len(o.Sinks))
+			for j := range pc.Sinks {
+				names = append(names, pc.sinkEntryName(j))
+			}
+			for j := range o.Sinks {
+				names = append(names, fmt.Sprintf("%s.%s", entryName("Overlays", i), entryName("Sinks", j)))
+			}
+			pc.Sinks = append(append([]SinkMatcher(nil), pc.Sinks...), o.Sinks...)
+			pc.sinkNames = names
+		}
+		if len(o.Sanitizers) > 0 {
+			pc.Sanitizers = append(append([]SanitizerMatcher(nil), pc.Sanitizers...), o.Sanitizers...)
+		}
+		if len(o.Exclude) > 0 {
+			pc.Exclude = append(append([]FuncMatcher(nil), pc.Exclude...), o.Exclude...)
+		}
+		if o.ReportMessage != "" {
+			pc.ReportMessage = o.ReportMessage
+		}
+	}
+	return pc
+}
+
+// sinkEntryName names the sink at a given index, e.g. "Sinks[0]".
+func (c Config) sinkEntryName(index int) string {
+	if index < len(c.sinkNames) {
+		return c.sinkNames[index]
+	}
+	return entryName("Sinks", index)
+}
+
+// readFile reads a configuration file, along with the files it includes.
+// Included files are relative to the directory of the including file.
+// The including files are used to detect cycles.
+func readFile(file string, including []string) (*Config, map[string]bool, error) {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, inc := range including {
+		if inc == abs {
+			return nil, nil, fmt.Errorf("config file %s includes itself", file)
+		}
+	}
+	bytes, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading analysis config: %v", err)
+	}
+	c, set, err := parse(bytes, filepath.Dir(file), append(including, abs))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %v", file, err)
+	}
+	return c, set, nil
+}
+
+// parse parses a configuration and merges it with the files it includes,
+// which are relative to dir. The included files are merged in order,
+// before the configuration itself: lists are concatenated, and scalar
+// settings replace those of the files merged before, if they are set.
+// The names of the settings that are set, in lowercase, are returned
+// along with the configuration.
+func parse(bytes []byte, dir string, including []string) (*Config, map[string]bool, error) {
+	c := new(Config)
+	if err := yaml.UnmarshalStrict(bytes, c); err != nil {
+		return nil, nil, err
+	}
+	var settings map[string]interface{}
+	if err := yaml.Unmarshal(bytes, &settings); err != nil {
+		return nil, nil, err
+	}
+	set := make(map[string]bool, len(settings))
+	for name := range settings {
+		set[strings.ToLower(name)] = true
+	}
+	if len(c.Include) == 0 {
+		return c, set, nil
+	}
+
+	merged, mergedSet := new(Config), make(map[string]bool)
+	for _, inc := range c.Include {
+		if !filepath.IsAbs(inc) {
+			inc = filepath.Join(dir, inc)
+		}
+		ic, icSet, err := readFile(inc, including)
+		if err != nil {
+			return nil, nil, err
+		}
+		merged.merge(ic, icSet)
+		for name := range icSet {
+			mergedSet[name] = true
+		}
+	}
+	merged.merge(c, set)
+	merged.Include = nil
+	for name := range set {
+		mergedSet[name] = true
+	}
+	return merged, mergedSet, nil
+}
+
+// merge merges other into c. Lists are concatenated, and the scalar
+// settings that are set in other replace those of c.
+func (c *Config) merge(other *Config, set map[string]bool) {
+	cv, ov := reflect.ValueOf(c).Elem(), reflect.ValueOf(other).Elem()
+	for i := 0; i < cv.NumField(); i++ {
+		field := cv.Type().Field(i)
+		if field.PkgPath != "" {
+			// Unexported fields are not settings.
+			continue
+		}
+		cf, of := cv.Field(i), ov.Field(i)
+		switch {
+		case field.Type.Kind() == reflect.Slice:
+			cf.Set(reflect.AppendSlice(cf, of))
+		case set[strings.ToLower(field.Name)]:
+			cf.Set(of)
+		}
+	}
+}
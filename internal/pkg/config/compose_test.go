@@ -0,0 +1,164 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/yaml"
+)
+
+func TestInclude(t *testing.T) {
+	conf, _, err := readFile("testdata/include/team.yaml", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Lists are concatenated, with the included entries first.
+	if got, want := conf.SinkEntries("log", "", "Printf"), []string{"Sinks[0]"}; !cmp.Equal(got, want) {
+		t.Errorf("got entries %v for the included sink, want %v", got, want)
+	}
+	if got, want := conf.SinkEntries("example.com/team/audit", "", "Record"), []string{"Sinks[1]"}; !cmp.Equal(got, want) {
+		t.Errorf("got entries %v for the team's sink, want %v", got, want)
+	}
+	for _, path := range []string{"example.com/vendored", "example.com/team/generated"} {
+		if !conf.IsExcluded(path, "", "f") {
+			t.Errorf("%s is not excluded", path)
+		}
+	}
+	if !conf.IsSourceType("example.com/core", "Credentials") {
+		t.Errorf("the included source is not a source")
+	}
+
+	// Scalar settings of the including file replace those of the included files,
+	// including when they are set to their zero value.
+	if conf.ReportMessage != "team message" {
+		t.Errorf("got report message %q, want %q", conf.ReportMessage, "team message")
+	}
+	if conf.InferSources {
+		t.Errorf("got InferSources = true, want the including file's false")
+	}
+	if !conf.UseEAR {
+		t.Errorf("got UseEAR = false, want the included file's true")
+	}
+	if len(conf.Include) != 0 {
+		t.Errorf("got includes %v after merging, want none", conf.Include)
+	}
+}
+
+func TestIncludeCycle(t *testing.T) {
+	_, _, err := readFile("testdata/include/cycle-a.yaml", nil)
+	if err == nil || !strings.Contains(err.Error(), "includes itself") {
+		t.Errorf("got err = %v, want an include cycle", err)
+	}
+}
+
+func TestOverlays(t *testing.T) {
+	conf := Config{}
+	err := yaml.UnmarshalStrict([]byte(`
+ReportMessage: "default message"
+Sinks:
+  - Package: "log"
+Overlays:
+  - PackageRE: "^example.com/payments/"
+    ReportMessage: "payments message"
+    Sinks:
+      - Package: "example.com/payments/ledger"
+        Method: "Write"
+    Sanitizers:
+      - Package: "example.com/payments/mask"
+  - Package: "example.com/payments/legacy"
+    Exclude:
+      - Package: "example.com/payments/legacy"
+`), &conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other := conf.ForPackage("example.com/search")
+	if other != &conf {
+		t.Errorf("got a new configuration for a package without overlays")
+	}
+	if other.IsSink("example.com/payments/ledger", "", "Write") || other.IsSanitizer("example.com/payments/mask", "", "Mask") {
+		t.Errorf("the overlay applies to a package that it does not match")
+	}
+
+	payments := conf.ForPackage("example.com/payments/api")
+	if payments.ReportMessage != "payments message" {
+		t.Errorf("got report message %q, want %q", payments.ReportMessage, "payments message")
+	}
+	if got, want := payments.SinkEntries("example.com/payments/ledger", "", "Write"), []string{"Overlays[0].Sinks[0]"}; !cmp.Equal(got, want) {
+		t.Errorf("got entries %v for the overlay's sink, want %v", got, want)
+	}
+	if got, want := payments.SinkEntries("log", "", "Printf"), []string{"Sinks[0]"}; !cmp.Equal(got, want) {
+		t.Errorf("got entries %v for the configuration's sink, want %v", got, want)
+	}
+	if !payments.IsSanitizer("example.com/payments/mask", "", "Mask") {
+		t.Errorf("the overlay's sanitizer is not a sanitizer")
+	}
+	if payments.IsExcluded("example.com/payments/legacy", "", "f") {
+		t.Errorf("the second overlay applies to a package that it does not match")
+	}
+
+	legacy := conf.ForPackage("example.com/payments/legacy")
+	if !legacy.IsExcluded("example.com/payments/legacy", "", "f") || !legacy.IsSink("example.com/payments/ledger", "", "Write") {
+		t.Errorf("the overlays matching a package are not all applied")
+	}
+
+	// Applying overlays does not modify the configuration.
+	if len(conf.Sinks) != 1 || conf.ReportMessage != "default message" {
+		t.Errorf("applying overlays modified the configuration")
+	}
+}
+
+func TestOverlayUnmarshalErrorCases(t *testing.T) {
+	testCases := []struct {
+		desc string
+		yaml string
+	}{
+		{
+			desc: "Overlays only add sinks, sanitizers, exclusions and report messages",
+			yaml: `
+PackageRE: "^example.com/"
+Sources:
+  - Package: "example.com/core"`,
+		},
+		{
+			desc: "Overlays use only one of Package and PackageRE",
+			yaml: `
+Package: "example.com/core"
+PackageRE: "^example.com/"`,
+		},
+		{
+			desc: "Overlay sinks are validated",
+			yaml: `
+PackageRE: "^example.com/"
+Sinks:
+  - Package: "log"
+    SensitiveArgs: [-1]`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			o := Overlay{}
+			if err := yaml.UnmarshalStrict([]byte(tc.yaml), &o); err == nil {
+				t.Errorf("expected error unmarshalling Overlay")
+			}
+		})
+	}
+}
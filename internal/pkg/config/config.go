@@ -18,14 +18,11 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
-	"io/ioutil"
 	"reflect"
 	"strconv"
 	"strings"
 	"sync"
 
-	"sigs.k8s.io/yaml"
-
 	"github.com/google/go-flow-levee/internal/pkg/config/regexp"
 )
 
@@ -54,17 +51,102 @@ func SetBytes(b []byte) {
 // Config contains matchers and analysis scope information.
 type Config struct {
 	ReportMessage             string
-	Sources                   []sourceMatcher
-	Sinks                     []funcMatcher
-	Sanitizers                []funcMatcher
-	FieldTags                 []fieldTagMatcher
-	Exclude                   []funcMatcher
+	Sources                   []SourceMatcher
+	SourceFunctions           []SourceFuncMatcher
+	Sinks                     []SinkMatcher
+	Sanitizers                []SanitizerMatcher
+	Validators                []ValidatorMatcher
+	FieldTags                 []FieldTagMatcher
+	Exclude                   []FuncMatcher
+	Summaries                 []SummaryMatcher
 	AllowPanicOnTaintedValues bool
+	// Whether suppressions must give a reason, e.g. levee.DoNotReport(reason="...").
+	RequireSuppressionReason bool
+	// Whether to report suppressions that do not suppress any finding.
+	ReportUnusedSuppressions bool
+	// Whether to treat types inferred to be sources as sources,
+	// e.g. types defined from a source type, or holding a field of a source type.
+	InferSources bool
+	// The call graph used to resolve the functions that may be called
+	// at a call site when looking for sinks and sanitizers.
+	CallGraph CallGraphType
 	// Whether to use EAR pointer analysis as the taint propagation engine.
 	UseEAR bool
 	// Control the span of the call chain from a source to a sink when analyzing EAR references.
 	// This can reduce false positives and enhance the performance.
 	EARTaintCallSpan uint
+	// The call graph used by the EAR engine to unify the arguments and the
+	// parameters of the functions that may be called, and to bound the call
+	// chains from sources to sinks.
+	EARCallGraph CallGraphType
+	// What distinguishes the calling contexts of a function in the EAR engine,
+	// whose depth is set by the -contextK flag.
+	EARContext ContextKind
+	// The sanitizer that suggested fixes wrap tainted sink arguments in.
+	FixSanitizer *FixSanitizer
+	// Configuration files merged before this one, relative to its directory.
+	// Once a file is read, its includes have been merged into it.
+	Include []string
+	// Settings added to the configuration of some packages.
+	Overlays []Overlay
+
+	// The names of the Sinks, if some were added by an overlay.
+	sinkNames []string
+}
+
+// Validate reports whether a configuration built in Go is valid,
+// according to the same rules as those applied to configuration files.
+func (c Config) Validate() error {
+	for i, ft := range c.FieldTags {
+		if err := ft.validate(); err != nil {
+			return fmt.Errorf("%s: %v", entryName("FieldTags", i), err)
+		}
+	}
+	for i, sfm := range c.SourceFunctions {
+		if err := sfm.validate(); err != nil {
+			return fmt.Errorf("%s: %v", entryName("SourceFunctions", i), err)
+		}
+	}
+	for i, sm := range c.Sinks {
+		if err := sm.validate(); err != nil {
+			return fmt.Errorf("%s: %v", entryName("Sinks", i), err)
+		}
+	}
+	for i, sm := range c.Sanitizers {
+		if err := sm.validate(); err != nil {
+			return fmt.Errorf("%s: %v", entryName("Sanitizers", i), err)
+		}
+	}
+	for i, sm := range c.Summaries {
+		if err := sm.validate(); err != nil {
+			return fmt.Errorf("%s: %v", entryName("Summaries", i), err)
+		}
+	}
+	for i, o := range c.Overlays {
+		for j, sm := range o.Sinks {
+			if err := sm.validate(); err != nil {
+				return fmt.Errorf("%s.%s: %v", entryName("Overlays", i), entryName("Sinks", j), err)
+			}
+		}
+		for j, sm := range o.Sanitizers {
+			if err := sm.validate(); err != nil {
+				return fmt.Errorf("%s.%s: %v", entryName("Overlays", i), entryName("Sanitizers", j), err)
+			}
+		}
+	}
+	if err := c.CallGraph.validate(); err != nil {
+		return err
+	}
+	if err := c.EARCallGraph.validate(); err != nil {
+		return err
+	}
+	if err := c.EARContext.validate(); err != nil {
+		return err
+	}
+	if c.FixSanitizer != nil {
+		return c.FixSanitizer.validate()
+	}
+	return nil
 }
 
 // IsSourceFieldTag determines whether a field tag made up of a key and value
@@ -111,6 +193,32 @@ func (c Config) IsSink(path, recv, name string) bool {
 	return false
 }
 
+// SinkEntries returns the names of the configured sinks matching a function,
+// e.g. "Sinks[0]".
+func (c Config) SinkEntries(path, recv, name string) []string {
+	var entries []string
+	for i, sink := range c.Sinks {
+		if sink.MatchFunction(path, recv, name) {
+			entries = append(entries, c.sinkEntryName(i))
+		}
+	}
+	return entries
+}
+
+// IsSinkArg determines whether the argument at a given position is sensitive
+// for a sink function, i.e. whether a value tainted with the given label
+// reaching the sink through that argument should be reported.
+// Positions are zero-based, and when it is present, the receiver counts
+// as the first argument.
+func (c Config) IsSinkArg(path, recv, name string, pos int, label string) bool {
+	for _, sink := range c.Sinks {
+		if sink.MatchFunction(path, recv, name) && sink.MatchArg(pos) && sink.MatchLabel(label) {
+			return true
+		}
+	}
+	return false
+}
+
 // IsSanitizer determines whether a function is a sanitizer.
 func (c Config) IsSanitizer(path, recv, name string) bool {
 	for _, san := range c.Sanitizers {
@@ -121,6 +229,63 @@ func (c Config) IsSanitizer(path, recv, name string) bool {
 	return false
 }
 
+// IsSanitizerForLabel determines whether a function is a sanitizer
+// for values tainted with the given label.
+func (c Config) IsSanitizerForLabel(path, recv, name, label string) bool {
+	for _, san := range c.Sanitizers {
+		if san.MatchFunction(path, recv, name) && san.MatchLabel(label) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsInPlaceSanitizerArg determines whether a sanitizer function sanitizes
+// the argument at a given position in place, so that the uses of the argument
+// that follow a call to the sanitizer are clean.
+// Positions are zero-based, and when it is present, the receiver counts
+// as the first argument.
+func (c Config) IsInPlaceSanitizerArg(path, recv, name string, pos int) bool {
+	for _, san := range c.Sanitizers {
+		if san.MatchFunction(path, recv, name) && san.MatchInPlaceArg(pos) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidator determines whether a function is a validator.
+func (c Config) IsValidator(path, recv, name string) bool {
+	for _, v := range c.Validators {
+		if v.MatchFunction(path, recv, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidatorForLabel determines whether a function is a validator
+// for values tainted with the given label.
+func (c Config) IsValidatorForLabel(path, recv, name, label string) bool {
+	for _, v := range c.Validators {
+		if v.MatchFunction(path, recv, name) && v.MatchLabel(label) {
+			return true
+		}
+	}
+	return false
+}
+
+// FindSummary returns the first configured summary for a function,
+// or nil if no configured summary matches the function.
+func (c Config) FindSummary(path, recv, name string) *FuncSummary {
+	for _, sm := range c.Summaries {
+		if sm.MatchFunction(path, recv, name) {
+			return &sm.FuncSummary
+		}
+	}
+	return nil
+}
+
 // IsSourceType determines whether a type is a source.
 func (c Config) IsSourceType(path, name string) bool {
 	for _, source := range c.Sources {
@@ -131,6 +296,133 @@ func (c Config) IsSourceType(path, name string) bool {
 	return false
 }
 
+// SourceTypeLabels returns the labels of the sources matching a type.
+// Sources that are not explicitly labeled have the DefaultLabel.
+func (c Config) SourceTypeLabels(path, name string) []string {
+	var labels []string
+	for _, source := range c.Sources {
+		if source.MatchType(path, name) {
+			labels = addLabel(labels, source.Label)
+		}
+	}
+	return labels
+}
+
+// SourceTypeEntries returns the names of the configured sources matching a type,
+// e.g. "Sources[0]".
+func (c Config) SourceTypeEntries(path, name string) []string {
+	var entries []string
+	for i, source := range c.Sources {
+		if source.MatchType(path, name) {
+			entries = append(entries, entryName("Sources", i))
+		}
+	}
+	return entries
+}
+
+// IsSourceFunctionResult determines whether the result at a given index
+// of a function is a source.
+func (c Config) IsSourceFunctionResult(path, recv, name string, index int) bool {
+	return len(c.SourceFunctionLabels(path, recv, name, index)) > 0
+}
+
+// SourceFunctionLabels returns the labels of the source functions
+// whose result at a given index is a source.
+func (c Config) SourceFunctionLabels(path, recv, name string, index int) []string {
+	var labels []string
+	for _, sf := range c.SourceFunctions {
+		if sf.MatchFunction(path, recv, name) && sf.MatchResult(index) {
+			labels = addLabel(labels, sf.Label)
+		}
+	}
+	return labels
+}
+
+// SourceFunctionEntries returns the names of the configured source functions
+// whose result at a given index is a source, e.g. "SourceFunctions[0]".
+func (c Config) SourceFunctionEntries(path, recv, name string, index int) []string {
+	var entries []string
+	for i, sf := range c.SourceFunctions {
+		if sf.MatchFunction(path, recv, name) && sf.MatchResult(index) {
+			entries = append(entries, entryName("SourceFunctions", i))
+		}
+	}
+	return entries
+}
+
+// entryName names the entry at a given index in a list of the configuration.
+func entryName(list string, index int) string {
+	return fmt.Sprintf("%s[%d]", list, index)
+}
+
+// EntryPatterns describes the patterns of the source or sink entry with
+// a given name, as returned by SourceTypeEntries, SourceFunctionEntries and
+// SinkEntries, e.g. `Package="example.com/core" MethodRE="^Sink"`.
+// Unlike the name, the description does not depend on the position of the
+// entry within the configuration.
+func (c Config) EntryPatterns(name string) string {
+	for i, source := range c.Sources {
+		if entryName("Sources", i) == name {
+			return describeMatchers([]string{"Package", "Type", "Field"}, source.Package, source.Type, source.Field)
+		}
+	}
+	for i, sf := range c.SourceFunctions {
+		if entryName("SourceFunctions", i) == name {
+			return sf.FuncMatcher.patterns()
+		}
+	}
+	for i, sink := range c.Sinks {
+		if c.sinkEntryName(i) == name {
+			return sink.FuncMatcher.patterns()
+		}
+	}
+	return ""
+}
+
+// Labels returns every label that a source can have,
+// including the DefaultLabel.
+func (c Config) Labels() []string {
+	labels := []string{DefaultLabel}
+	for _, source := range c.Sources {
+		labels = addLabel(labels, source.Label)
+	}
+	for _, sf := range c.SourceFunctions {
+		labels = addLabel(labels, sf.Label)
+	}
+	return labels
+}
+
+// DefaultLabel is the label of sources that are not explicitly labeled.
+const DefaultLabel = ""
+
+func addLabel(labels []string, label string) []string {
+	for _, l := range labels {
+		if l == label {
+			return labels
+		}
+	}
+	return append(labels, label)
+}
+
+// LabelMatcher restricts a sink or a sanitizer to some labels.
+// If Labels is empty, all labels are matched.
+type LabelMatcher struct {
+	Labels []string
+}
+
+// MatchLabel determines whether a label is matched.
+func (lm LabelMatcher) MatchLabel(label string) bool {
+	if len(lm.Labels) == 0 {
+		return true
+	}
+	for _, l := range lm.Labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
 // IsSourceField determines whether a field is a source.
 func (c Config) IsSourceField(path, typeName, fieldName string) bool {
 	for _, source := range c.Sources {
@@ -141,10 +433,31 @@ func (c Config) IsSourceField(path, typeName, fieldName string) bool {
 	return false
 }
 
-type stringMatcher interface {
+// A StringMatcher matches names, such as package paths and function names.
+// A nil StringMatcher matches any name.
+type StringMatcher interface {
 	MatchString(string) bool
 }
 
+// Literal returns a StringMatcher that matches s exactly.
+func Literal(s string) StringMatcher {
+	return literalMatcher(s)
+}
+
+// Regexp returns a StringMatcher that matches the names matched
+// by the regular expression expr.
+func Regexp(expr string) (StringMatcher, error) {
+	r, err := regexp.New(expr)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func matchString(m StringMatcher, s string) bool {
+	return m == nil || m.MatchString(s)
+}
+
 type literalMatcher string
 
 func (lm literalMatcher) MatchString(s string) bool {
@@ -157,7 +470,30 @@ func (vacuousMatcher) MatchString(string) bool {
 	return true
 }
 
-type fieldTagMatcher struct {
+// describeMatchers describes the matchers of the given keys,
+// omitting those that match any name.
+func describeMatchers(keys []string, matchers ...StringMatcher) string {
+	var parts []string
+	for i, m := range matchers {
+		switch t := m.(type) {
+		case nil, vacuousMatcher:
+			continue
+		case literalMatcher:
+			parts = append(parts, fmt.Sprintf("%s=%q", keys[i], string(t)))
+		case *literalMatcher:
+			parts = append(parts, fmt.Sprintf("%s=%q", keys[i], string(*t)))
+		case *regexp.Regexp:
+			parts = append(parts, fmt.Sprintf("%sRE=%q", keys[i], t.String()))
+		default:
+			parts = append(parts, fmt.Sprintf("%s=%v", keys[i], t))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// A FieldTagMatcher matches struct fields whose tag holds Value,
+// possibly among other comma-separated values, for Key.
+type FieldTagMatcher struct {
 	Key   string
 	Value string
 }
@@ -168,7 +504,7 @@ type rawFieldTagMatcher struct {
 	Value string
 }
 
-func (ft *fieldTagMatcher) UnmarshalJSON(bytes []byte) error {
+func (ft *FieldTagMatcher) UnmarshalJSON(bytes []byte) error {
 	validFieldTagMatcherFields := []string{"key", "value"}
 	if err := validateFieldNames(&bytes, "fieldTagMatcher", validFieldTagMatcherFields); err != nil {
 		return err
@@ -179,22 +515,27 @@ func (ft *fieldTagMatcher) UnmarshalJSON(bytes []byte) error {
 		return err
 	}
 
-	if raw.Key == "" {
+	m := FieldTagMatcher{Key: raw.Key, Value: raw.Value}
+	if err := m.validate(); err != nil {
+		return err
+	}
+	*ft = m
+	return nil
+}
+
+func (ft FieldTagMatcher) validate() error {
+	if ft.Key == "" {
 		return fmt.Errorf("invalid field tag matcher: please provide a non-empty Key")
 	}
-	if raw.Value == "" {
+	if ft.Value == "" {
 		return fmt.Errorf("invalid field tag matcher: please provide a non-empty Value")
 	}
-
-	ft.Key = raw.Key
-	ft.Value = raw.Value
-
 	return nil
 }
 
 // Returns the first non-nil matcher.
 // If all are nil, returns a vacuousMatcher.
-func matcherFrom(lm *literalMatcher, r *regexp.Regexp) stringMatcher {
+func matcherFrom(lm *literalMatcher, r *regexp.Regexp) StringMatcher {
 	switch {
 	case lm != nil:
 		return lm
@@ -205,13 +546,16 @@ func matcherFrom(lm *literalMatcher, r *regexp.Regexp) stringMatcher {
 	}
 }
 
-// A sourceMatcher matches by package, type, and field.
+// A SourceMatcher matches by package, type, and field.
 // Matching may be done against string literals Package, Type, Field,
 // or against regexp PackageRE, TypeRE, FieldRE.
-type sourceMatcher struct {
-	Package stringMatcher
-	Type    stringMatcher
-	Field   stringMatcher
+// Label is used to distinguish between different kinds of sources,
+// e.g. credentials and personal information.
+type SourceMatcher struct {
+	Package StringMatcher
+	Type    StringMatcher
+	Field   StringMatcher
+	Label   string
 }
 
 // this type uses the default unmarshaller and mirrors configuration key-value pairs
@@ -222,10 +566,11 @@ type rawSourceMatcher struct {
 	PackageRE *regexp.Regexp
 	TypeRE    *regexp.Regexp
 	FieldRE   *regexp.Regexp
+	Label     string
 }
 
-func (s *sourceMatcher) UnmarshalJSON(bytes []byte) error {
-	validSourceMatcherFields := []string{"package", "packageRE", "type", "typeRE", "field", "fieldRE"}
+func (s *SourceMatcher) UnmarshalJSON(bytes []byte) error {
+	validSourceMatcherFields := []string{"package", "packageRE", "type", "typeRE", "field", "fieldRE", "label"}
 	if err := validateFieldNames(&bytes, "sourceMatcher", validSourceMatcherFields); err != nil {
 		return err
 	}
@@ -246,26 +591,29 @@ func (s *sourceMatcher) UnmarshalJSON(bytes []byte) error {
 		return fmt.Errorf("expected only one of Field, FieldRE in config definition for a source matcher")
 	}
 
-	*s = sourceMatcher{
+	*s = SourceMatcher{
 		Package: matcherFrom(raw.Package, raw.PackageRE),
 		Type:    matcherFrom(raw.Type, raw.TypeRE),
 		Field:   matcherFrom(raw.Field, raw.FieldRE),
+		Label:   raw.Label,
 	}
 	return nil
 }
 
-func (s sourceMatcher) MatchType(path, typeName string) bool {
-	return s.Package.MatchString(path) && s.Type.MatchString(typeName)
+func (s SourceMatcher) MatchType(path, typeName string) bool {
+	return matchString(s.Package, path) && matchString(s.Type, typeName)
 }
 
-func (s sourceMatcher) MatchField(path, typeName, fieldName string) bool {
-	return s.MatchType(path, typeName) && s.Field.MatchString(fieldName)
+func (s SourceMatcher) MatchField(path, typeName, fieldName string) bool {
+	return s.MatchType(path, typeName) && matchString(s.Field, fieldName)
 }
 
-type funcMatcher struct {
-	Package  stringMatcher
-	Receiver stringMatcher
-	Method   stringMatcher
+// A FuncMatcher matches functions by package, receiver, and name.
+// Functions that are not methods have an empty receiver.
+type FuncMatcher struct {
+	Package  StringMatcher
+	Receiver StringMatcher
+	Method   StringMatcher
 }
 
 // this type uses the default unmarshaller and mirrors configuration key-value pairs
@@ -278,7 +626,7 @@ type rawFuncMatcher struct {
 	MethodRE   *regexp.Regexp
 }
 
-func (fm *funcMatcher) UnmarshalJSON(bytes []byte) error {
+func (fm *FuncMatcher) UnmarshalJSON(bytes []byte) error {
 	validFuncMatcherFields := []string{"package", "packageRE", "receiver", "receiverRE", "method", "methodRE"}
 	if err := validateFieldNames(&bytes, "funcMatcher", validFuncMatcherFields); err != nil {
 		return err
@@ -289,27 +637,541 @@ func (fm *funcMatcher) UnmarshalJSON(bytes []byte) error {
 		return err
 	}
 
+	m, err := newFuncMatcher(raw)
+	if err != nil {
+		return err
+	}
+	*fm = m
+	return nil
+}
+
+func newFuncMatcher(raw rawFuncMatcher) (FuncMatcher, error) {
 	// Only one of literal and regexp field can be specified.
 	if raw.Package != nil && raw.PackageRE != nil {
-		return fmt.Errorf("expected only one of Package, PackageRE in config definition for a function matcher")
+		return FuncMatcher{}, fmt.Errorf("expected only one of Package, PackageRE in config definition for a function matcher")
 	}
 	if raw.Receiver != nil && raw.ReceiverRE != nil {
-		return fmt.Errorf("expected only one of Receiver, ReceiverRE in config definition for a function matcher")
+		return FuncMatcher{}, fmt.Errorf("expected only one of Receiver, ReceiverRE in config definition for a function matcher")
 	}
 	if raw.Method != nil && raw.MethodRE != nil {
-		return fmt.Errorf("expected only one of Method, MethodRE in config definition for a function matcher")
+		return FuncMatcher{}, fmt.Errorf("expected only one of Method, MethodRE in config definition for a function matcher")
 	}
 
-	*fm = funcMatcher{
+	return FuncMatcher{
 		Package:  matcherFrom(raw.Package, raw.PackageRE),
 		Receiver: matcherFrom(raw.Receiver, raw.ReceiverRE),
 		Method:   matcherFrom(raw.Method, raw.MethodRE),
+	}, nil
+}
+
+func (fm FuncMatcher) MatchFunction(path, receiver, name string) bool {
+	return matchString(fm.Package, path) && matchString(fm.Receiver, receiver) && matchString(fm.Method, name)
+}
+
+// patterns describes the patterns of a FuncMatcher, see EntryPatterns.
+func (fm FuncMatcher) patterns() string {
+	return describeMatchers([]string{"Package", "Receiver", "Method"}, fm.Package, fm.Receiver, fm.Method)
+}
+
+// A SourceFuncMatcher matches functions whose results are sources.
+// If Results is empty, all of a function's results are sources.
+type SourceFuncMatcher struct {
+	FuncMatcher
+	// Results holds the zero-based indexes of the results that are sources.
+	Results []int
+	Label   string
+}
+
+// this type uses the default unmarshaller and mirrors configuration key-value pairs
+type rawSourceFuncMatcher struct {
+	rawFuncMatcher
+	Results []int
+	Label   string
+}
+
+func (sfm *SourceFuncMatcher) UnmarshalJSON(bytes []byte) error {
+	validSourceFuncMatcherFields := []string{"package", "packageRE", "receiver", "receiverRE", "method", "methodRE", "results", "label"}
+	if err := validateFieldNames(&bytes, "sourceFuncMatcher", validSourceFuncMatcherFields); err != nil {
+		return err
+	}
+
+	raw := rawSourceFuncMatcher{}
+	if err := json.Unmarshal(bytes, &raw); err != nil {
+		return err
+	}
+
+	fm, err := newFuncMatcher(raw.rawFuncMatcher)
+	if err != nil {
+		return err
+	}
+
+	m := SourceFuncMatcher{
+		FuncMatcher: fm,
+		Results:     raw.Results,
+		Label:       raw.Label,
+	}
+	if err := m.validate(); err != nil {
+		return err
+	}
+	*sfm = m
+	return nil
+}
+
+func (sfm SourceFuncMatcher) validate() error {
+	for _, r := range sfm.Results {
+		if r < 0 {
+			return fmt.Errorf("invalid source function: result index %d is negative", r)
+		}
+	}
+	return nil
+}
+
+// MatchResult determines whether the result at a given index is a source.
+func (sfm SourceFuncMatcher) MatchResult(index int) bool {
+	if len(sfm.Results) == 0 {
+		return true
+	}
+	for _, r := range sfm.Results {
+		if r == index {
+			return true
+		}
+	}
+	return false
+}
+
+// A SinkMatcher matches sink functions. If SensitiveArgs is empty,
+// all of a sink's arguments are sensitive.
+type SinkMatcher struct {
+	FuncMatcher
+	LabelMatcher
+	// SensitiveArgs holds the positions of the sensitive arguments.
+	// Positions follow the same convention as in FuncSummary.
+	SensitiveArgs []int
+}
+
+// this type uses the default unmarshaller and mirrors configuration key-value pairs
+type rawSinkMatcher struct {
+	rawFuncMatcher
+	SensitiveArgs []int
+	Labels        []string
+}
+
+func (sm *SinkMatcher) UnmarshalJSON(bytes []byte) error {
+	validSinkMatcherFields := []string{"package", "packageRE", "receiver", "receiverRE", "method", "methodRE", "sensitiveArgs", "labels"}
+	if err := validateFieldNames(&bytes, "sinkMatcher", validSinkMatcherFields); err != nil {
+		return err
+	}
+
+	raw := rawSinkMatcher{}
+	if err := json.Unmarshal(bytes, &raw); err != nil {
+		return err
+	}
+
+	fm, err := newFuncMatcher(raw.rawFuncMatcher)
+	if err != nil {
+		return err
+	}
+
+	m := SinkMatcher{
+		FuncMatcher:   fm,
+		LabelMatcher:  LabelMatcher{Labels: raw.Labels},
+		SensitiveArgs: raw.SensitiveArgs,
+	}
+	if err := m.validate(); err != nil {
+		return err
+	}
+	*sm = m
+	return nil
+}
+
+func (sm SinkMatcher) validate() error {
+	for _, p := range sm.SensitiveArgs {
+		if p < 0 {
+			return fmt.Errorf("invalid sink: position %d is negative", p)
+		}
 	}
 	return nil
 }
 
-func (fm funcMatcher) MatchFunction(path, receiver, name string) bool {
-	return fm.Package.MatchString(path) && fm.Receiver.MatchString(receiver) && fm.Method.MatchString(name)
+// MatchArg determines whether the argument at a given position is sensitive.
+func (sm SinkMatcher) MatchArg(pos int) bool {
+	if len(sm.SensitiveArgs) == 0 {
+		return true
+	}
+	for _, p := range sm.SensitiveArgs {
+		if p == pos {
+			return true
+		}
+	}
+	return false
+}
+
+// A SanitizerMatcher matches sanitizer functions. If Labels is empty,
+// a sanitizer applies to all labels. If InPlaceArgs is empty,
+// a sanitizer may sanitize any of its arguments in place.
+type SanitizerMatcher struct {
+	FuncMatcher
+	LabelMatcher
+	// InPlaceArgs holds the positions of the arguments that the sanitizer
+	// sanitizes in place, e.g. a pointer whose sensitive fields it clears.
+	// Positions follow the same convention as in FuncSummary.
+	InPlaceArgs []int
+}
+
+// this type uses the default unmarshaller and mirrors configuration key-value pairs
+type rawSanitizerMatcher struct {
+	rawFuncMatcher
+	InPlaceArgs []int
+	Labels      []string
+}
+
+func (sm *SanitizerMatcher) UnmarshalJSON(bytes []byte) error {
+	validSanitizerMatcherFields := []string{"package", "packageRE", "receiver", "receiverRE", "method", "methodRE", "inPlaceArgs", "labels"}
+	if err := validateFieldNames(&bytes, "sanitizerMatcher", validSanitizerMatcherFields); err != nil {
+		return err
+	}
+
+	raw := rawSanitizerMatcher{}
+	if err := json.Unmarshal(bytes, &raw); err != nil {
+		return err
+	}
+
+	fm, err := newFuncMatcher(raw.rawFuncMatcher)
+	if err != nil {
+		return err
+	}
+
+	m := SanitizerMatcher{
+		FuncMatcher:  fm,
+		LabelMatcher: LabelMatcher{Labels: raw.Labels},
+		InPlaceArgs:  raw.InPlaceArgs,
+	}
+	if err := m.validate(); err != nil {
+		return err
+	}
+	*sm = m
+	return nil
+}
+
+func (sm SanitizerMatcher) validate() error {
+	for _, p := range sm.InPlaceArgs {
+		if p < 0 {
+			return fmt.Errorf("invalid sanitizer: position %d is negative", p)
+		}
+	}
+	return nil
+}
+
+// MatchInPlaceArg determines whether the argument at a given position
+// is sanitized in place.
+func (sm SanitizerMatcher) MatchInPlaceArg(pos int) bool {
+	if len(sm.InPlaceArgs) == 0 {
+		return true
+	}
+	for _, p := range sm.InPlaceArgs {
+		if p == pos {
+			return true
+		}
+	}
+	return false
+}
+
+// A ValidatorMatcher matches validator functions, which check their
+// arguments instead of transforming them. A validator returns a bool,
+// which is true if its arguments are safe, or an error, which is nil
+// if they are. If Labels is empty, a validator applies to all labels.
+type ValidatorMatcher struct {
+	FuncMatcher
+	LabelMatcher
+}
+
+// this type uses the default unmarshaller and mirrors configuration key-value pairs
+type rawValidatorMatcher struct {
+	rawFuncMatcher
+	Labels []string
+}
+
+func (vm *ValidatorMatcher) UnmarshalJSON(bytes []byte) error {
+	validValidatorMatcherFields := []string{"package", "packageRE", "receiver", "receiverRE", "method", "methodRE", "labels"}
+	if err := validateFieldNames(&bytes, "validatorMatcher", validValidatorMatcherFields); err != nil {
+		return err
+	}
+
+	raw := rawValidatorMatcher{}
+	if err := json.Unmarshal(bytes, &raw); err != nil {
+		return err
+	}
+
+	fm, err := newFuncMatcher(raw.rawFuncMatcher)
+	if err != nil {
+		return err
+	}
+
+	*vm = ValidatorMatcher{
+		FuncMatcher:  fm,
+		LabelMatcher: LabelMatcher{Labels: raw.Labels},
+	}
+	return nil
+}
+
+// A FixSanitizer identifies the sanitizer function inserted by suggested fixes.
+// A fix is only suggested if the function is also matched by the Sanitizers
+// for every label with which taint reaches the sink.
+type FixSanitizer struct {
+	// Package is the import path of the package declaring the sanitizer.
+	Package string
+	// Method is the name of the sanitizer, which must be a function.
+	Method string
+}
+
+// this type uses the default unmarshaller and mirrors configuration key-value pairs
+type rawFixSanitizer FixSanitizer
+
+func (fs *FixSanitizer) UnmarshalJSON(bytes []byte) error {
+	validFixSanitizerFields := []string{"package", "method"}
+	if err := validateFieldNames(&bytes, "FixSanitizer", validFixSanitizerFields); err != nil {
+		return err
+	}
+
+	raw := rawFixSanitizer{}
+	if err := json.Unmarshal(bytes, &raw); err != nil {
+		return err
+	}
+
+	m := FixSanitizer(raw)
+	if err := m.validate(); err != nil {
+		return err
+	}
+	*fs = m
+	return nil
+}
+
+func (fs FixSanitizer) validate() error {
+	if fs.Package == "" || fs.Method == "" {
+		return fmt.Errorf("invalid FixSanitizer: please provide both a Package and a Method")
+	}
+	return nil
+}
+
+// A CallGraphType selects the algorithm used to construct a call graph.
+type CallGraphType string
+
+const (
+	// StaticCallGraph only resolves static calls. This is the default.
+	StaticCallGraph CallGraphType = "static"
+	// CHACallGraph uses Class Hierarchy Analysis.
+	CHACallGraph CallGraphType = "cha"
+	// RTACallGraph uses Rapid Type Analysis.
+	RTACallGraph CallGraphType = "rta"
+	// VTACallGraph uses Variable Type Analysis.
+	VTACallGraph CallGraphType = "vta"
+)
+
+func (t *CallGraphType) UnmarshalJSON(bytes []byte) error {
+	var raw string
+	if err := json.Unmarshal(bytes, &raw); err != nil {
+		return err
+	}
+	switch cg := CallGraphType(strings.ToLower(raw)); cg {
+	case StaticCallGraph, CHACallGraph, RTACallGraph, VTACallGraph:
+		*t = cg
+		return nil
+	}
+	return fmt.Errorf("invalid call graph %q: please provide one of static, cha, rta, vta", raw)
+}
+
+// validate accepts the empty CallGraphType, which selects the static call graph.
+func (t CallGraphType) validate() error {
+	switch t {
+	case "", StaticCallGraph, CHACallGraph, RTACallGraph, VTACallGraph:
+		return nil
+	}
+	return fmt.Errorf("invalid call graph %q: please provide one of static, cha, rta, vta", string(t))
+}
+
+// A ContextKind selects what distinguishes the calling contexts of a function
+// in the EAR engine.
+type ContextKind string
+
+const (
+	// CallSiteContext distinguishes the calls to a function by their call sites.
+	// This is the default.
+	CallSiteContext ContextKind = "callsite"
+	// ObjectContext distinguishes the calls to a method by the allocation site
+	// of their receiver, and the other calls by their call sites.
+	ObjectContext ContextKind = "object"
+	// TypeContext distinguishes the calls to a method by the type of the object
+	// allocated at the allocation site of their receiver, and the other calls
+	// by their call sites.
+	TypeContext ContextKind = "type"
+)
+
+func (k *ContextKind) UnmarshalJSON(bytes []byte) error {
+	var raw string
+	if err := json.Unmarshal(bytes, &raw); err != nil {
+		return err
+	}
+	switch kind := ContextKind(strings.ToLower(raw)); kind {
+	case CallSiteContext, ObjectContext, TypeContext:
+		*k = kind
+		return nil
+	}
+	return fmt.Errorf("invalid context %q: please provide one of callsite, object, type", raw)
+}
+
+// validate accepts the empty ContextKind, which selects call site contexts.
+func (k ContextKind) validate() error {
+	switch k {
+	case "", CallSiteContext, ObjectContext, TypeContext:
+		return nil
+	}
+	return fmt.Errorf("invalid context %q: please provide one of callsite, object, type", string(k))
+}
+
+// An Engine selects the engines finding the sources that reach sinks.
+// It is set by the -engine flag of the analyzers running the engines.
+type Engine string
+
+const (
+	// PropagationEngine follows the propagation of taint from SSA values to their referrers.
+	PropagationEngine Engine = "propagation"
+	// EAREngine uses the EAR pointer analysis.
+	EAREngine Engine = "ear"
+	// BothEngines runs both engines, and reports the findings of only one of them.
+	BothEngines Engine = "both"
+)
+
+func (e *Engine) String() string {
+	return string(*e)
+}
+
+// Set implements flag.Value. The empty Engine leaves the choice to UseEAR.
+func (e *Engine) Set(s string) error {
+	switch engine := Engine(strings.ToLower(s)); engine {
+	case "", PropagationEngine, EAREngine, BothEngines:
+		*e = engine
+		return nil
+	}
+	return fmt.Errorf("invalid engine %q: please provide one of propagation, ear, both", s)
+}
+
+// Engines determines whether the propagation and EAR engines run, given the
+// engine selected by the -engine flag. If no engine is selected,
+// the EAR engine runs if UseEAR is set, and the propagation engine otherwise.
+func (c *Config) Engines(selected Engine) (propagation, ear bool) {
+	switch selected {
+	case PropagationEngine:
+		return true, false
+	case EAREngine:
+		return false, true
+	case BothEngines:
+		return true, true
+	}
+	return !c.UseEAR, c.UseEAR
+}
+
+// A FuncSummary describes how taint propagates through a function.
+// Positions are zero-based, and when it is present, the receiver
+// counts as the first argument. A variadic parameter is a single argument.
+type FuncSummary struct {
+	// IfTainted holds the positions of the arguments that, if tainted,
+	// cause taint to propagate to TaintedArgs and TaintedRets.
+	IfTainted []int
+	// TaintedArgs holds the positions of the arguments that become tainted.
+	TaintedArgs []int
+	// TaintedRets holds the positions of the return values that become tainted.
+	TaintedRets []int
+}
+
+// A SummaryMatcher associates a FuncSummary with the functions
+// matched by its embedded FuncMatcher.
+type SummaryMatcher struct {
+	FuncMatcher
+	FuncSummary
+}
+
+// this type uses the default unmarshaller and mirrors configuration key-value pairs
+type rawSummaryMatcher struct {
+	rawFuncMatcher
+	IfTainted   []int
+	TaintedArgs []int
+	TaintedRets []int
+}
+
+func (sm *SummaryMatcher) UnmarshalJSON(bytes []byte) error {
+	validSummaryMatcherFields := []string{"package", "packageRE", "receiver", "receiverRE", "method", "methodRE", "ifTainted", "taintedArgs", "taintedRets"}
+	if err := validateFieldNames(&bytes, "summaryMatcher", validSummaryMatcherFields); err != nil {
+		return err
+	}
+
+	raw := rawSummaryMatcher{}
+	if err := json.Unmarshal(bytes, &raw); err != nil {
+		return err
+	}
+
+	fm, err := newFuncMatcher(raw.rawFuncMatcher)
+	if err != nil {
+		return err
+	}
+
+	m := SummaryMatcher{
+		FuncMatcher: fm,
+		FuncSummary: FuncSummary{
+			IfTainted:   raw.IfTainted,
+			TaintedArgs: raw.TaintedArgs,
+			TaintedRets: raw.TaintedRets,
+		},
+	}
+	if err := m.validate(); err != nil {
+		return err
+	}
+	*sm = m
+	return nil
+}
+
+func (sm SummaryMatcher) validate() error {
+	if len(sm.IfTainted) == 0 {
+		return fmt.Errorf("invalid summary: please provide a non-empty IfTainted")
+	}
+	if len(sm.TaintedArgs) == 0 && len(sm.TaintedRets) == 0 {
+		return fmt.Errorf("invalid summary: please provide at least one of TaintedArgs, TaintedRets")
+	}
+	for _, positions := range [][]int{sm.IfTainted, sm.TaintedArgs, sm.TaintedRets} {
+		for _, p := range positions {
+			// IfTainted is represented as a 64-bit set when propagating taint.
+			if p < 0 || p >= 64 {
+				return fmt.Errorf("invalid summary: position %d is out of range [0, 64)", p)
+			}
+		}
+	}
+	return nil
+}
+
+// Load returns the configuration of the package at path, from conf if it
+// is not nil, or otherwise from the configuration read by ReadConfig.
+// Analyzers bound to a configuration use it as is, while the others read
+// the configuration selected by their -config flag.
+func Load(conf *Config, path string) (*Config, error) {
+	if conf == nil {
+		var err error
+		if conf, err = ReadConfig(); err != nil {
+			return nil, err
+		}
+	}
+	return conf.ForPackage(path), nil
+}
+
+// NewFlagSet returns the flags of an analyzer bound to conf.
+// Analyzers that are not bound to a configuration, i.e. for which conf is nil,
+// share the -config flag of FlagSet. Analyzers may add their own flags.
+func NewFlagSet(conf *Config) flag.FlagSet {
+	var fs flag.FlagSet
+	if conf == nil {
+		FlagSet.VisitAll(func(f *flag.Flag) {
+			fs.Var(f.Value, f.Name, f.Usage)
+		})
+	}
+	return fs
 }
 
 // ReadConfig reads configuration from the config cache.
@@ -325,8 +1187,8 @@ func ReadConfig() (*Config, error) {
 
 func readConfigBytes() (*Config, error) {
 	configBytesOnce.Do(func() {
-		configFromBytes = new(Config)
-		configFromBytesErr = yaml.UnmarshalStrict(configBytes, configFromBytes)
+		// Files included by the config bytes are relative to the working directory.
+		configFromBytes, _, configFromBytesErr = parse(configBytes, ".", nil)
 		if configFromBytesErr != nil {
 			fmt.Println(configFromBytesErr)
 		}
@@ -345,15 +1207,8 @@ type configCacheElement struct {
 
 func (r *configCacheElement) readOnce() (*Config, error) {
 	r.once.Do(func() {
-		c := new(Config)
-		bytes, err := ioutil.ReadFile(r.sourceFile)
+		c, _, err := readFile(r.sourceFile, nil)
 		if err != nil {
-			fmt.Println(err)
-			r.err = fmt.Errorf("error reading analysis config: %v", err)
-			return
-		}
-
-		if err := yaml.UnmarshalStrict(bytes, c); err != nil {
 			fmt.Println(err)
 			r.err = err
 			return
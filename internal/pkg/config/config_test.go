@@ -18,11 +18,13 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-flow-levee/internal/pkg/utils"
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/analysistest"
 	"golang.org/x/tools/go/analysis/passes/buildssa"
 	"golang.org/x/tools/go/ssa"
+	"sigs.k8s.io/yaml"
 )
 
 var testAnalyzer = &analysis.Analyzer{
@@ -93,3 +95,399 @@ func TestConfig(t *testing.T) {
 	}
 	analysistest.Run(t, testdata, testAnalyzer, "./...")
 }
+
+func TestCallGraphType(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		yaml    string
+		want    CallGraphType
+		wantErr bool
+	}{
+		{
+			desc: "Default to no call graph type",
+			yaml: `UseEAR: false`,
+			want: "",
+		},
+		{
+			desc: "Call graph types are case-insensitive",
+			yaml: `CallGraph: VTA`,
+			want: VTACallGraph,
+		},
+		{
+			desc:    "Unknown call graph types are rejected",
+			yaml:    `CallGraph: pointer`,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			conf := Config{}
+			err := yaml.UnmarshalStrict([]byte(tc.yaml), &conf)
+
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got err = %v, want error: %v", err, tc.wantErr)
+			}
+			if conf.CallGraph != tc.want {
+				t.Errorf("got call graph %q, want %q", conf.CallGraph, tc.want)
+			}
+		})
+	}
+}
+
+func TestContextKind(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		yaml    string
+		want    ContextKind
+		wantErr bool
+	}{
+		{
+			desc: "Default to no context kind",
+			yaml: `UseEAR: true`,
+			want: "",
+		},
+		{
+			desc: "Context kinds are case-insensitive",
+			yaml: `EARContext: Object`,
+			want: ObjectContext,
+		},
+		{
+			desc:    "Unknown context kinds are rejected",
+			yaml:    `EARContext: receiver`,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			conf := Config{}
+			err := yaml.UnmarshalStrict([]byte(tc.yaml), &conf)
+
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got err = %v, want error: %v", err, tc.wantErr)
+			}
+			if conf.EARContext != tc.want {
+				t.Errorf("got context kind %q, want %q", conf.EARContext, tc.want)
+			}
+		})
+	}
+}
+
+func TestEngines(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		flag            string
+		useEAR          bool
+		wantPropagation bool
+		wantEAR         bool
+		wantErr         bool
+	}{
+		{
+			desc:            "Default to the propagation engine",
+			wantPropagation: true,
+		},
+		{
+			desc:    "Default to the EAR engine with UseEAR",
+			useEAR:  true,
+			wantEAR: true,
+		},
+		{
+			desc:            "The flag selects the engine regardless of UseEAR",
+			flag:            "propagation",
+			useEAR:          true,
+			wantPropagation: true,
+		},
+		{
+			desc:            "Both engines, case-insensitively",
+			flag:            "Both",
+			wantPropagation: true,
+			wantEAR:         true,
+		},
+		{
+			desc:            "Unknown engines are rejected",
+			flag:            "points-to",
+			wantPropagation: true,
+			wantErr:         true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var engine Engine
+			err := engine.Set(tc.flag)
+
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got err = %v, want error: %v", err, tc.wantErr)
+			}
+			propagation, ear := (&Config{UseEAR: tc.useEAR}).Engines(engine)
+			if propagation != tc.wantPropagation || ear != tc.wantEAR {
+				t.Errorf("got engines propagation=%v, ear=%v, want propagation=%v, ear=%v", propagation, ear, tc.wantPropagation, tc.wantEAR)
+			}
+		})
+	}
+}
+
+func TestEntries(t *testing.T) {
+	conf := Config{}
+	err := yaml.UnmarshalStrict([]byte(`
+Sources:
+- Package: "example.com/core"
+  Type: "Source"
+- PackageRE: "example.com/.*"
+  TypeRE: "Secret|Source"
+SourceFunctions:
+- Package: "os"
+  Method: "Getenv"
+Sinks:
+- Package: "log"
+- Package: "example.com/core"
+  Method: "Sink"
+`), &conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := conf.SourceTypeEntries("example.com/core", "Source"), []string{"Sources[0]", "Sources[1]"}; !cmp.Equal(got, want) {
+		t.Errorf("SourceTypeEntries = %v, want %v", got, want)
+	}
+	if got, want := conf.SourceFunctionEntries("os", "", "Getenv", 0), []string{"SourceFunctions[0]"}; !cmp.Equal(got, want) {
+		t.Errorf("SourceFunctionEntries = %v, want %v", got, want)
+	}
+	if got, want := conf.SinkEntries("example.com/core", "", "Sink"), []string{"Sinks[1]"}; !cmp.Equal(got, want) {
+		t.Errorf("SinkEntries = %v, want %v", got, want)
+	}
+	if got := conf.SinkEntries("fmt", "", "Println"); got != nil {
+		t.Errorf("SinkEntries = %v, want none", got)
+	}
+}
+
+func TestEntryPatterns(t *testing.T) {
+	conf := Config{}
+	err := yaml.UnmarshalStrict([]byte(`
+Sources:
+- Package: "example.com/core"
+  Type: "Source"
+- PackageRE: "example.com/.*"
+  TypeRE: "Secret|Source"
+  Field: "Data"
+SourceFunctions:
+- Package: "os"
+  Method: "Getenv"
+Sinks:
+- Package: "log"
+- Package: "example.com/core"
+  Receiver: "Logger"
+  MethodRE: "^Sink"
+`), &conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]string{
+		"Sources[0]":         `Package="example.com/core" Type="Source"`,
+		"Sources[1]":         `PackageRE="example.com/.*" TypeRE="Secret|Source" Field="Data"`,
+		"SourceFunctions[0]": `Package="os" Method="Getenv"`,
+		"Sinks[0]":           `Package="log"`,
+		"Sinks[1]":           `Package="example.com/core" Receiver="Logger" MethodRE="^Sink"`,
+		"Sinks[2]":           "",
+	} {
+		if got := conf.EntryPatterns(name); got != want {
+			t.Errorf("EntryPatterns(%q) = %s, want %s", name, got, want)
+		}
+	}
+}
+
+func TestInPlaceSanitizers(t *testing.T) {
+	conf := Config{}
+	err := yaml.UnmarshalStrict([]byte(`
+Sanitizers:
+- Package: "example.com/redact"
+  Method: "Into"
+  InPlaceArgs: [0]
+- Package: "example.com/redact"
+  Method: "Scrub"
+`), &conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !conf.IsInPlaceSanitizerArg("example.com/redact", "", "Into", 0) {
+		t.Errorf("the first argument of Into is not sanitized in place")
+	}
+	if conf.IsInPlaceSanitizerArg("example.com/redact", "", "Into", 1) {
+		t.Errorf("the second argument of Into is sanitized in place")
+	}
+	if !conf.IsInPlaceSanitizerArg("example.com/redact", "", "Scrub", 1) {
+		t.Errorf("the second argument of Scrub is not sanitized in place")
+	}
+}
+
+func TestValidators(t *testing.T) {
+	conf := Config{}
+	err := yaml.UnmarshalStrict([]byte(`
+Validators:
+- Package: "example.com/users"
+  Method: "IsPublicEmail"
+  Labels: ["pii"]
+- PackageRE: "^example.com/auth$"
+  MethodRE: "^Check"
+`), &conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !conf.IsValidator("example.com/users", "", "IsPublicEmail") {
+		t.Errorf("IsPublicEmail is not a validator")
+	}
+	if conf.IsSanitizer("example.com/users", "", "IsPublicEmail") {
+		t.Errorf("IsPublicEmail is a sanitizer")
+	}
+	if !conf.IsValidatorForLabel("example.com/users", "", "IsPublicEmail", "pii") {
+		t.Errorf("IsPublicEmail is not a validator for pii")
+	}
+	if conf.IsValidatorForLabel("example.com/users", "", "IsPublicEmail", "credentials") {
+		t.Errorf("IsPublicEmail is a validator for credentials")
+	}
+	if !conf.IsValidatorForLabel("example.com/auth", "", "CheckRedacted", "credentials") {
+		t.Errorf("CheckRedacted is not a validator for credentials")
+	}
+
+	err = yaml.UnmarshalStrict([]byte(`
+Validators:
+- Package: "example.com/users"
+  PackageRE: "example.com/.*"
+`), &Config{})
+	if err == nil {
+		t.Errorf("got no error for a validator with both a literal and a regexp package")
+	}
+}
+
+func TestFixSanitizer(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		yaml    string
+		want    *FixSanitizer
+		wantErr bool
+	}{
+		{
+			desc: "Default to no fix sanitizer",
+			yaml: `UseEAR: false`,
+		},
+		{
+			desc: "Fix sanitizers name a package and a function",
+			yaml: `
+FixSanitizer:
+  Package: "example.com/redact"
+  Method: "Redact"`,
+			want: &FixSanitizer{Package: "example.com/redact", Method: "Redact"},
+		},
+		{
+			desc: "Fix sanitizers require a package",
+			yaml: `
+FixSanitizer:
+  Method: "Redact"`,
+			wantErr: true,
+		},
+		{
+			desc: "Fix sanitizers require a function",
+			yaml: `
+FixSanitizer:
+  Package: "example.com/redact"`,
+			wantErr: true,
+		},
+		{
+			desc: "Fix sanitizers are not matched by regexp",
+			yaml: `
+FixSanitizer:
+  Package: "example.com/redact"
+  MethodRE: "^Redact"`,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			conf := Config{}
+			err := yaml.UnmarshalStrict([]byte(tc.yaml), &conf)
+
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got err = %v, want error: %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if !cmp.Equal(conf.FixSanitizer, tc.want) {
+				t.Errorf("got fix sanitizer %v, want %v", conf.FixSanitizer, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		conf    Config
+		wantErr string
+	}{
+		{
+			desc: "Matchers may be left nil",
+			conf: Config{
+				Sources: []SourceMatcher{{Type: Literal("Source")}},
+				Sinks:   []SinkMatcher{{SensitiveArgs: []int{0}}},
+			},
+		},
+		{
+			desc:    "Sink positions are not negative",
+			conf:    Config{Sinks: []SinkMatcher{{}, {SensitiveArgs: []int{-1}}}},
+			wantErr: "Sinks[1]: invalid sink: position -1 is negative",
+		},
+		{
+			desc:    "Sanitizer positions are not negative",
+			conf:    Config{Sanitizers: []SanitizerMatcher{{InPlaceArgs: []int{-1}}}},
+			wantErr: "Sanitizers[0]: invalid sanitizer: position -1 is negative",
+		},
+		{
+			desc:    "Summaries have an IfTainted",
+			conf:    Config{Summaries: []SummaryMatcher{{FuncSummary: FuncSummary{TaintedRets: []int{0}}}}},
+			wantErr: "Summaries[0]: invalid summary: please provide a non-empty IfTainted",
+		},
+		{
+			desc:    "Field tags have a key",
+			conf:    Config{FieldTags: []FieldTagMatcher{{Value: "secret"}}},
+			wantErr: "FieldTags[0]: invalid field tag matcher: please provide a non-empty Key",
+		},
+		{
+			desc:    "Call graphs are known",
+			conf:    Config{CallGraph: "pointer"},
+			wantErr: `invalid call graph "pointer": please provide one of static, cha, rta, vta`,
+		},
+		{
+			desc:    "EAR call graphs are known",
+			conf:    Config{EARCallGraph: "pointer"},
+			wantErr: `invalid call graph "pointer": please provide one of static, cha, rta, vta`,
+		},
+		{
+			desc:    "EAR contexts are known",
+			conf:    Config{EARContext: "receiver"},
+			wantErr: `invalid context "receiver": please provide one of callsite, object, type`,
+		},
+		{
+			desc:    "Fix sanitizers name a function",
+			conf:    Config{FixSanitizer: &FixSanitizer{Package: "example.com/redact"}},
+			wantErr: "invalid FixSanitizer: please provide both a Package and a Method",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var got string
+			if err := tc.conf.Validate(); err != nil {
+				got = err.Error()
+			}
+			if got != tc.wantErr {
+				t.Errorf("got err = %q, want %q", got, tc.wantErr)
+			}
+		})
+	}
+}
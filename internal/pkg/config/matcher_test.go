@@ -17,6 +17,7 @@ package config
 import (
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-flow-levee/internal/pkg/config/regexp"
 	"sigs.k8s.io/yaml"
 )
@@ -63,7 +64,7 @@ MethodRE: bar`,
 
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
-			fm := funcMatcher{}
+			fm := FuncMatcher{}
 			err := yaml.UnmarshalStrict([]byte(tc.yaml), &fm)
 
 			if err == nil {
@@ -135,9 +136,9 @@ Method: bar`,
 
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
-			fm := funcMatcher{}
+			fm := FuncMatcher{}
 			if err := yaml.UnmarshalStrict([]byte(tc.yaml), &fm); err != nil {
-				t.Errorf("unexpected error unmarshalling funcMatcher: %v", err)
+				t.Errorf("unexpected error unmarshalling FuncMatcher: %v", err)
 			}
 
 			if tc.shouldMatch != fm.MatchFunction(tc.path, tc.recv, tc.name) {
@@ -189,7 +190,7 @@ FieldRE: bar`,
 
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
-			sm := sourceMatcher{}
+			sm := SourceMatcher{}
 			err := yaml.UnmarshalStrict([]byte(tc.yaml), &sm)
 
 			if err == nil {
@@ -267,9 +268,9 @@ Field: qux`,
 
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
-			sm := sourceMatcher{}
+			sm := SourceMatcher{}
 			if err := yaml.UnmarshalStrict([]byte(tc.yaml), &sm); err != nil {
-				t.Errorf("Unexpected error unmarshalling sourceMatcher: %v", err)
+				t.Errorf("Unexpected error unmarshalling SourceMatcher: %v", err)
 			}
 
 			if tc.shouldMatchType != sm.MatchType(tc.path, tc.typ) {
@@ -330,7 +331,7 @@ Value: bar`,
 
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
-			ftm := fieldTagMatcher{}
+			ftm := FieldTagMatcher{}
 			err := yaml.UnmarshalStrict([]byte(tc.yaml), &ftm)
 
 			if (err != nil) != tc.wantErr {
@@ -343,7 +344,7 @@ Value: bar`,
 func TestMatcherTypes(t *testing.T) {
 	testCases := []struct {
 		desc        string
-		matcher     stringMatcher
+		matcher     StringMatcher
 		s           string
 		shouldMatch bool
 	}{
@@ -367,19 +368,19 @@ func TestMatcherTypes(t *testing.T) {
 		},
 		{
 			desc:        "regexp matcher /foo/ matches foo",
-			matcher:     func() stringMatcher { r, _ := regexp.New("foo"); return r }(),
+			matcher:     func() StringMatcher { r, _ := regexp.New("foo"); return r }(),
 			s:           "foo",
 			shouldMatch: true,
 		},
 		{
 			desc:        "regexp matcher /foo/ matches food",
-			matcher:     func() stringMatcher { r, _ := regexp.New("foo"); return r }(),
+			matcher:     func() StringMatcher { r, _ := regexp.New("foo"); return r }(),
 			s:           "food",
 			shouldMatch: true,
 		},
 		{
 			desc:        "regexp matcher /foo/ does not match bar",
-			matcher:     func() stringMatcher { r, _ := regexp.New("foo"); return r }(),
+			matcher:     func() StringMatcher { r, _ := regexp.New("foo"); return r }(),
 			s:           "bar",
 			shouldMatch: false,
 		},
@@ -401,13 +402,388 @@ func TestMatcherTypes(t *testing.T) {
 			s:           "bar",
 			shouldMatch: true,
 		},
+		{
+			desc:        "nil matcher matches foo",
+			matcher:     nil,
+			s:           "foo",
+			shouldMatch: true,
+		},
+		{
+			desc:        "Literal(foo) does not match food",
+			matcher:     Literal("foo"),
+			s:           "food",
+			shouldMatch: false,
+		},
+		{
+			desc:        "Regexp(^foo$) does not match food",
+			matcher:     func() StringMatcher { r, _ := Regexp("^foo$"); return r }(),
+			s:           "food",
+			shouldMatch: false,
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
-			if tc.matcher.MatchString(tc.s) != tc.shouldMatch {
+			if matchString(tc.matcher, tc.s) != tc.shouldMatch {
 				t.Errorf("matcher (%T) %v returned MatchString(%q) == %v, want %v, ", tc.matcher, tc.matcher, tc.s, !tc.shouldMatch, tc.shouldMatch)
 			}
 		})
 	}
 }
+
+func TestSummaryMatcherUnmarshalErrorCases(t *testing.T) {
+	testCases := []struct {
+		desc, yaml string
+	}{
+		{
+			desc: "Unmarshaling is strict",
+			yaml: `
+Method: foo
+IfTainted: [0]
+TaintedRets: [0]
+Blahblah: bar`,
+		},
+		{
+			desc: "Do not permit both Method and MethodRE",
+			yaml: `
+Method: foo
+MethodRE: bar
+IfTainted: [0]
+TaintedRets: [0]`,
+		},
+		{
+			desc: "Require IfTainted",
+			yaml: `
+Method: foo
+TaintedRets: [0]`,
+		},
+		{
+			desc: "Require at least one of TaintedArgs and TaintedRets",
+			yaml: `
+Method: foo
+IfTainted: [0]`,
+		},
+		{
+			desc: "Do not permit negative positions",
+			yaml: `
+Method: foo
+IfTainted: [-1]
+TaintedRets: [0]`,
+		},
+		{
+			desc: "Do not permit positions that do not fit in the IfTainted bitset",
+			yaml: `
+Method: foo
+IfTainted: [64]
+TaintedRets: [0]`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sm := SummaryMatcher{}
+			err := yaml.UnmarshalStrict([]byte(tc.yaml), &sm)
+
+			if err == nil {
+				t.Error("got err = nil, want error")
+			}
+		})
+	}
+}
+
+func TestFindSummary(t *testing.T) {
+	conf := Config{}
+	yml := `
+Summaries:
+- Package: foo
+  Receiver: Bar
+  Method: Baz
+  IfTainted: [1]
+  TaintedRets: [0]
+- Package: foo
+  Method: Baz
+  IfTainted: [0]
+  TaintedArgs: [1]
+`
+	if err := yaml.UnmarshalStrict([]byte(yml), &conf); err != nil {
+		t.Fatalf("unexpected error unmarshalling config: %v", err)
+	}
+
+	testCases := []struct {
+		desc             string
+		path, recv, name string
+		want             *FuncSummary
+	}{
+		{
+			desc: "The first matching summary is returned",
+			path: "foo",
+			recv: "Bar",
+			name: "Baz",
+			want: &FuncSummary{IfTainted: []int{1}, TaintedRets: []int{0}},
+		},
+		{
+			desc: "A summary without a receiver matches any receiver",
+			path: "foo",
+			recv: "Qux",
+			name: "Baz",
+			want: &FuncSummary{IfTainted: []int{0}, TaintedArgs: []int{1}},
+		},
+		{
+			desc: "No summary is returned if no summary matches",
+			path: "foo",
+			recv: "",
+			name: "Qux",
+			want: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := conf.FindSummary(tc.path, tc.recv, tc.name)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("FindSummary(%q, %q, %q) diff (-want +got):\n%s", tc.path, tc.recv, tc.name, diff)
+			}
+		})
+	}
+}
+
+func TestIsSinkArg(t *testing.T) {
+	conf := Config{}
+	yml := `
+Sinks:
+- Package: foo
+  Method: Log
+  SensitiveArgs: [1, 2]
+- Package: foo
+  Method: Sink
+`
+	if err := yaml.UnmarshalStrict([]byte(yml), &conf); err != nil {
+		t.Fatalf("unexpected error unmarshalling config: %v", err)
+	}
+
+	testCases := []struct {
+		desc        string
+		name        string
+		pos         int
+		shouldMatch bool
+	}{
+		{
+			desc:        "Listed position is sensitive",
+			name:        "Log",
+			pos:         1,
+			shouldMatch: true,
+		},
+		{
+			desc:        "Unlisted position is not sensitive",
+			name:        "Log",
+			pos:         0,
+			shouldMatch: false,
+		},
+		{
+			desc:        "Every position is sensitive if none are listed",
+			name:        "Sink",
+			pos:         5,
+			shouldMatch: true,
+		},
+		{
+			desc:        "Positions of functions that are not sinks are not sensitive",
+			name:        "Other",
+			pos:         0,
+			shouldMatch: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := conf.IsSinkArg("foo", "", tc.name, tc.pos, DefaultLabel); got != tc.shouldMatch {
+				t.Errorf("IsSinkArg(%q, %q, %q, %d, %q) got %v, want %v", "foo", "", tc.name, tc.pos, DefaultLabel, got, tc.shouldMatch)
+			}
+		})
+	}
+}
+
+func TestSinkMatcherUnmarshalErrorCases(t *testing.T) {
+	testCases := []struct {
+		desc, yaml string
+	}{
+		{
+			desc: "Unmarshaling is strict",
+			yaml: `
+Method: foo
+Blahblah: bar`,
+		},
+		{
+			desc: "Do not permit negative positions",
+			yaml: `
+Method: foo
+SensitiveArgs: [-1]`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sm := SinkMatcher{}
+			err := yaml.UnmarshalStrict([]byte(tc.yaml), &sm)
+
+			if err == nil {
+				t.Error("got err = nil, want error")
+			}
+		})
+	}
+}
+
+func TestIsSourceFunctionResult(t *testing.T) {
+	conf := Config{}
+	yml := `
+SourceFunctions:
+- Package: os
+  Method: Getenv
+- Package: vault
+  Receiver: "*Client"
+  Method: Read
+  Results: [0]
+`
+	if err := yaml.UnmarshalStrict([]byte(yml), &conf); err != nil {
+		t.Fatalf("unexpected error unmarshalling config: %v", err)
+	}
+
+	testCases := []struct {
+		desc             string
+		path, recv, name string
+		index            int
+		shouldMatch      bool
+	}{
+		{
+			desc:        "Every result is a source if no results are listed",
+			path:        "os",
+			name:        "Getenv",
+			index:       0,
+			shouldMatch: true,
+		},
+		{
+			desc:        "Listed result is a source",
+			path:        "vault",
+			recv:        "*Client",
+			name:        "Read",
+			index:       0,
+			shouldMatch: true,
+		},
+		{
+			desc:        "Unlisted result is not a source",
+			path:        "vault",
+			recv:        "*Client",
+			name:        "Read",
+			index:       1,
+			shouldMatch: false,
+		},
+		{
+			desc:        "Results of other functions are not sources",
+			path:        "os",
+			name:        "Hostname",
+			index:       0,
+			shouldMatch: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := conf.IsSourceFunctionResult(tc.path, tc.recv, tc.name, tc.index); got != tc.shouldMatch {
+				t.Errorf("IsSourceFunctionResult(%q, %q, %q, %d) got %v, want %v", tc.path, tc.recv, tc.name, tc.index, got, tc.shouldMatch)
+			}
+		})
+	}
+}
+
+func TestSourceFuncMatcherUnmarshalErrorCases(t *testing.T) {
+	testCases := []struct {
+		desc, yaml string
+	}{
+		{
+			desc: "Unmarshaling is strict",
+			yaml: `
+Method: foo
+Blahblah: bar`,
+		},
+		{
+			desc: "Do not permit negative result indexes",
+			yaml: `
+Method: foo
+Results: [-1]`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sfm := SourceFuncMatcher{}
+			err := yaml.UnmarshalStrict([]byte(tc.yaml), &sfm)
+
+			if err == nil {
+				t.Error("got err = nil, want error")
+			}
+		})
+	}
+}
+
+func TestLabels(t *testing.T) {
+	conf := Config{}
+	yml := `
+Sources:
+- Package: foo
+  Type: Credentials
+  Label: credentials
+- Package: foo
+  Type: User
+  Field: Password
+  Label: credentials
+- Package: foo
+  Type: User
+  Field: Email
+  Label: pii
+- Package: foo
+  Type: Unlabeled
+Sinks:
+- Package: foo
+  Method: Log
+  Labels: [credentials]
+- Package: foo
+  Method: Sink
+Sanitizers:
+- Package: foo
+  Method: Redact
+  Labels: [pii]
+`
+	if err := yaml.UnmarshalStrict([]byte(yml), &conf); err != nil {
+		t.Fatalf("unexpected error unmarshalling config: %v", err)
+	}
+
+	typeLabels := []struct {
+		typeName string
+		want     []string
+	}{
+		{"Credentials", []string{"credentials"}},
+		{"User", []string{"credentials", "pii"}},
+		{"Unlabeled", []string{DefaultLabel}},
+		{"Other", nil},
+	}
+	for _, tl := range typeLabels {
+		if diff := cmp.Diff(tl.want, conf.SourceTypeLabels("foo", tl.typeName)); diff != "" {
+			t.Errorf("SourceTypeLabels(%q, %q) diff (-want +got):\n%s", "foo", tl.typeName, diff)
+		}
+	}
+
+	if !conf.IsSinkArg("foo", "", "Log", 0, "credentials") {
+		t.Error("Log should be a sink for credentials")
+	}
+	if conf.IsSinkArg("foo", "", "Log", 0, "pii") {
+		t.Error("Log should not be a sink for pii")
+	}
+	if !conf.IsSinkArg("foo", "", "Sink", 0, "pii") {
+		t.Error("Sink should be a sink for all labels")
+	}
+	if !conf.IsSanitizerForLabel("foo", "", "Redact", "pii") {
+		t.Error("Redact should be a sanitizer for pii")
+	}
+	if conf.IsSanitizerForLabel("foo", "", "Redact", "credentials") {
+		t.Error("Redact should not be a sanitizer for credentials")
+	}
+}
@@ -36,6 +36,14 @@ func (mr *Regexp) MatchString(s string) bool {
 	return mr.r == nil || mr.r.MatchString(s)
 }
 
+// String returns the source text of the regular expression.
+func (mr *Regexp) String() string {
+	if mr == nil || mr.r == nil {
+		return ""
+	}
+	return mr.r.String()
+}
+
 // UnmarshalJSON implementation of json.UnmarshalJSON interface.
 func (mr *Regexp) UnmarshalJSON(data []byte) error {
 	var matcher string
@@ -16,13 +16,17 @@ package earpointer
 
 import (
 	"fmt"
+	"go/build"
 	"go/token"
 	"go/types"
+	"path/filepath"
 	"reflect"
 	"strconv"
 	"strings"
 
+	"github.com/google/go-flow-levee/internal/pkg/callees"
 	"github.com/google/go-flow-levee/internal/pkg/config"
+	"github.com/google/go-flow-levee/internal/pkg/utils"
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/buildssa"
@@ -32,24 +36,42 @@ import (
 	"golang.org/x/tools/go/ssa/ssautil"
 )
 
-var (
-	contextK = 0 // the number of call sites in each context
-)
-
-func init() {
-	Analyzer.Flags.IntVar(&contextK, "contextK", 0,
+// Analyzer traverses the packages and constructs an EAR partitions
+// unifying all the IR elements in these packages.
+// It reads its configuration from the file selected by the -config flag.
+var Analyzer = NewAnalyzer(nil, callees.Analyzer)
+
+// NewAnalyzer returns an analyzer bound to conf, which requires the
+// resolvedCallees analyzer bound to the same configuration, and shares
+// its call graphs. If conf is nil, the analyzer reads its configuration
+// as Analyzer does. Each analyzer has its own -contextK flag.
+func NewAnalyzer(conf *config.Config, resolvedCallees *analysis.Analyzer) *analysis.Analyzer {
+	a := &analysis.Analyzer{
+		Name:       "earpointer",
+		Doc:        "EAR pointer analysis",
+		Flags:      config.NewFlagSet(conf),
+		ResultType: reflect.TypeOf(new(Partitions)),
+		Requires:   []*analysis.Analyzer{buildssa.Analyzer, resolvedCallees},
+		FactTypes:  []analysis.Fact{new(heapSummary)},
+	}
+	// The number of call sites in each context.
+	contextK := a.Flags.Int("contextK", 0,
 		`the K value (default=0) in context sensitivity.`)
+	// The analysis only runs for the EAR engine.
+	engine := new(config.Engine)
+	a.Flags.Var(engine, "engine", "the engines finding the sources reaching sinks: propagation, ear, or both to report the findings of only one of them (default: ear if UseEAR is set, propagation otherwise)")
+	a.Run = func(pass *analysis.Pass) (interface{}, error) {
+		// The callees are not resolved when the analyzer is run by itself, as in tests.
+		resolved, _ := pass.ResultOf[resolvedCallees].(callees.ResultType)
+		return run(pass, conf, *contextK, *engine, resolved)
+	}
+	return a
 }
 
-// Analyzer traverses the packages and constructs an EAR partitions
-// unifying all the IR elements in these packages.
-var Analyzer = &analysis.Analyzer{
-	Name:       "earpointer",
-	Doc:        "EAR pointer analysis",
-	Flags:      config.FlagSet,
-	Run:        run,
-	ResultType: reflect.TypeOf(new(Partitions)),
-	Requires:   []*analysis.Analyzer{buildssa.Analyzer},
+// EngineFlag returns the value of the -engine flag of an analyzer
+// returned by NewAnalyzer, which the analyzers requiring it may share.
+func EngineFlag(earPointer *analysis.Analyzer) *config.Engine {
+	return earPointer.Flags.Lookup("engine").Value.(*config.Engine)
 }
 
 // visitor traverse the instructions in a function and perform unifications
@@ -60,36 +82,61 @@ type visitor struct {
 	callees  map[*ssa.CallCommon][]*ssa.Function // callee functions at each callsite
 	contexts map[*ssa.Function][]*Context        // for context sensitive analysis
 	contextK int
+	config   *config.Config
+	// The summaries of the functions of other packages.
+	summaries map[types.Object]*heapSummary
 }
 
-func run(pass *analysis.Pass) (interface{}, error) {
-	conf, err := config.ReadConfig()
+func run(pass *analysis.Pass, conf *config.Config, contextK int, engine config.Engine, resolved callees.ResultType) (interface{}, error) {
+	ssainput := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
+	conf, err := config.Load(conf, ssainput.Pkg.Pkg.Path())
 	if err != nil {
 		return nil, err
 	}
-	if !conf.UseEAR {
+	// The dependencies are analyzed for their facts, except for the standard
+	// library, whose functions the EAR analysis does not model (TODO(#312)).
+	if _, ear := conf.Engines(engine); !ear || isStandardLibrary(pass) {
 		return &Partitions{}, nil
 	}
-	ssainput := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
-	p := analyze(ssainput)
+	summaries := make(map[types.Object]*heapSummary)
+	for _, f := range pass.AllObjectFacts() {
+		summaries[f.Object] = f.Fact.(*heapSummary)
+	}
+	p := analyze(ssainput, conf, contextK, summaries, resolved.CallGraph(conf.EARCallGraph))
+	for fn, s := range summarize(ssainput.Pkg, ssainput.SrcFuncs, p, conf) {
+		pass.ExportObjectFact(fn.Object(), s)
+	}
 	return p, nil
 }
 
+// Return whether a pass analyzes a package of the standard library,
+// whose files are in GOROOT.
+func isStandardLibrary(pass *analysis.Pass) bool {
+	if len(pass.Files) == 0 {
+		return false
+	}
+	file := pass.Fset.File(pass.Files[0].Pos())
+	return file != nil && strings.HasPrefix(file.Name(), filepath.Join(build.Default.GOROOT, "src")+string(filepath.Separator))
+}
+
 // Analyzes an SSA program and build the partition information.
-func analyze(ssainput *buildssa.SSA) *Partitions {
+// The calls to functions of other packages are handled using their summaries.
+// If the given call graph is nil, the static call graph is used.
+func analyze(ssainput *buildssa.SSA, conf *config.Config, contextK int, summaries map[types.Object]*heapSummary, cg *callgraph.Graph) *Partitions {
 	prog := ssainput.Pkg.Prog
 	// Use the call graph to initialize the contexts.
-	// TODO: the call graph can be CHA, RTA, VTA, etc.
-	cg := static.CallGraph(prog)
-	vis := visitor{state: NewState(), callees: mapCallees(cg)}
+	if cg == nil {
+		cg = static.CallGraph(prog)
+	}
+	vis := visitor{state: NewState(), callees: mapCallees(cg), contextK: contextK, config: conf, summaries: summaries}
 	vis.initContexts(cg)
-	vis.initGlobalReferences(ssainput.Pkg)
 	// Analyze all the functions and methods in the package,
 	// not just those in ssainput.SrcFuncs.
 	fns := ssautil.AllFunctions(prog)
 	for _, fn := range ssainput.SrcFuncs {
 		fns[fn] = true
 	}
+	vis.initGlobalReferences(ssainput.Pkg, fns)
 	for fn := range fns {
 		vis.initFunction(fn)
 	}
@@ -102,24 +149,26 @@ func analyze(ssainput *buildssa.SSA) *Partitions {
 	}
 	p := vis.state.ToPartitions()
 	p.cg = cg
+	p.callees = vis.callees
+	p.summaries = summaries
 	return p
 }
 
 // Builds the calling context set for each function.
 func (vis *visitor) initContexts(cg *callgraph.Graph) {
-	vis.contextK = contextK
 	vis.contexts = make(map[*ssa.Function][]*Context)
 	for fn, node := range cg.Nodes {
 		if fn == nil {
 			continue
 		}
 		// Create the contexts for this function.
-		vis.contexts[fn] = collectContext(node, vis.contextK)
+		vis.contexts[fn] = vis.collectContext(node, vis.contextK)
 	}
 }
 
 // Collect the contexts from the node backward up to k edges.
-func collectContext(node *callgraph.Node, k int) []*Context {
+// Contexts made of the same elements are collected once.
+func (vis *visitor) collectContext(node *callgraph.Node, k int) []*Context {
 	// This implementation can be optimized in two ways:
 	// (1) Reuse the k-1, k-2, ... contexts when calculating k so as to
 	//     avoid duplicate computations.
@@ -131,27 +180,120 @@ func collectContext(node *callgraph.Node, k int) []*Context {
 	}
 	var kContexts []*Context
 	for _, in := range node.In {
-		for _, prev := range collectContext(in.Caller, k-1) {
-			cur := append(*prev, in.Site)
+		elem := vis.contextElement(in.Site, in.Callee.Func)
+	prevs:
+		for _, prev := range vis.collectContext(in.Caller, k-1) {
+			cur := append(*prev, elem)
+			for _, c := range kContexts {
+				if contextEqual(*c, cur) {
+					continue prevs
+				}
+			}
 			kContexts = append(kContexts, &cur)
 		}
 	}
 	return kContexts
 }
 
-// Insert into the state all the global references.
-func (vis *visitor) initGlobalReferences(pkg *ssa.Package) {
+// Return the element distinguishing the calls to a function made
+// at a call site, according to the configured kind of context.
+// Object and type sensitivity only apply to calls to methods
+// whose receiver's allocation site is known; otherwise the call site is used.
+func (vis *visitor) contextElement(site ssa.CallInstruction, callee *ssa.Function) ContextElement {
+	kind := vis.config.EARContext
+	if kind != config.ObjectContext && kind != config.TypeContext {
+		return site
+	}
+	args := utils.CallArgs(site.Common())
+	if callee.Signature.Recv() == nil || len(args) == 0 {
+		return site
+	}
+	alloc := allocationSite(args[0])
+	if alloc == nil {
+		return site
+	}
+	if kind == config.TypeContext {
+		return utils.Dereference(alloc.Type())
+	}
+	return allocationElement{alloc}
+}
+
+// An allocation site as an element of an object-sensitive context,
+// printed as the local it defines (e.g. "f.t0").
+type allocationElement struct {
+	alloc ssa.Value
+}
+
+func (e allocationElement) String() string {
+	return e.alloc.Parent().Name() + "." + e.alloc.Name()
+}
+
+// Return the instruction allocating the object that holds a value, if it can
+// be determined within the value's function. For example, for "t1 = &t0.x"
+// where "t0 = new T", the allocation site of t1 is t0, as t1 points into t0.
+// The result of a call is considered allocated by the call.
+func allocationSite(v ssa.Value) ssa.Value {
+	switch v := v.(type) {
+	case *ssa.Alloc, *ssa.Call, *ssa.MakeMap, *ssa.MakeSlice, *ssa.MakeChan, *ssa.MakeClosure:
+		return v
+	case *ssa.FieldAddr:
+		return allocationSite(v.X)
+	case *ssa.IndexAddr:
+		return allocationSite(v.X)
+	case *ssa.Field:
+		return allocationSite(v.X)
+	case *ssa.MakeInterface:
+		return allocationSite(v.X)
+	case *ssa.ChangeInterface:
+		return allocationSite(v.X)
+	case *ssa.ChangeType:
+		return allocationSite(v.X)
+	}
+	return nil
+}
+
+// Return whether two contexts are made of the same elements.
+func contextEqual(c1, c2 Context) bool {
+	if len(c1) != len(c2) {
+		return false
+	}
+	for i := range c1 {
+		if c1[i] != c2[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Insert into the state all the global references of a package, and those of
+// other packages that are referred to by the functions being analyzed.
+func (vis *visitor) initGlobalReferences(pkg *ssa.Package, fns map[*ssa.Function]bool) {
 	state := vis.state
+	initGlobal := func(g *ssa.Global) {
+		if typeMayShareObject(g.Type()) &&
+			// skip some synthetic variables
+			!strings.HasPrefix(g.Name(), "init$") {
+			state.Insert(MakeGlobal(g))
+		}
+	}
 	for _, member := range pkg.Members {
 		if g, ok := member.(*ssa.Global); ok {
-			if typeMayShareObject(g.Type()) &&
-				// skip some synthetic variables
-				!strings.HasPrefix(g.Name(), "init$") {
-				state.Insert(MakeGlobal(g))
-			}
+			initGlobal(g)
 		}
 		// Global constants are not within the scope of the pointer analysis.
 	}
+	var operands []*ssa.Value
+	for fn := range fns {
+		for _, b := range fn.Blocks {
+			for _, instr := range b.Instrs {
+				for _, op := range instr.Operands(operands[:0]) {
+					if g, ok := (*op).(*ssa.Global); ok && g.Pkg != pkg {
+						initGlobal(g)
+					}
+				}
+			}
+		}
+	}
 }
 
 // Insert into the state all the local references.
@@ -515,8 +657,14 @@ func (vis *visitor) visitCall(call *ssa.CallCommon, callsite ssa.Instruction) {
 			// special handling of some known functions
 			continue
 		}
+		if summ := vis.summaries[fn.Object()]; summ != nil && len(fn.Blocks) == 0 {
+			vis.visitHeapSummary(summ, fn, callsite)
+			continue
+		}
 
 		paramCstrs, retCstrs := vis.collectCalleeConstraints(call, fn, callsite)
+		// The element distinguishing the callee's context for this call.
+		elem := vis.contextElement(callsite.(ssa.CallInstruction), fn)
 		// Unify caller arguments and callee parameters in matching contexts.
 		for arg, params := range paramCstrs {
 			for _, param := range params {
@@ -529,7 +677,7 @@ func (vis *visitor) visitCall(call *ssa.CallCommon, callsite ssa.Instruction) {
 				// SSA creates a copy of v and passes it to g. Hence we directly
 				// unify this copy and g's argument "a".
 				if mayShareObject(arg) {
-					vis.unifyCallWithContexts(arg, param, callsite)
+					vis.unifyCallWithContexts(arg, param, elem)
 				}
 			}
 		}
@@ -539,7 +687,7 @@ func (vis *visitor) visitCall(call *ssa.CallCommon, callsite ssa.Instruction) {
 				if len(calleeRet) == 1 { // non-tuple case
 					param := calleeRet[0]
 					if mayShareObject(param) {
-						vis.unifyCallWithContexts(callerRet, param, callsite)
+						vis.unifyCallWithContexts(callerRet, param, elem)
 					}
 					continue
 				}
@@ -557,7 +705,7 @@ func (vis *visitor) visitCall(call *ssa.CallCommon, callsite ssa.Instruction) {
 					field := Field{Name: strconv.Itoa(k)}
 					// unify instance field
 					for _, c := range vis.getContexts(callerRet) {
-						callerCxt := append(*c, callsite)
+						callerCxt := append(*c, elem)
 						for _, calleeCxt := range vis.getContexts(retV) {
 							if vis.contextKEqual(callerCxt, *calleeCxt) {
 								vis.unifyField(c, callerRet, field, retV)
@@ -570,14 +718,15 @@ func (vis *visitor) visitCall(call *ssa.CallCommon, callsite ssa.Instruction) {
 	}
 }
 
-// Unify the argument in the caller with the parameter in the callee under all contexts
-func (vis *visitor) unifyCallWithContexts(arg ssa.Value, param ssa.Value, callsite ssa.Instruction) {
+// Unify the argument in the caller with the parameter in the callee under all contexts.
+// Argument "elem" is the context element of the call, e.g. the call site.
+func (vis *visitor) unifyCallWithContexts(arg ssa.Value, param ssa.Value, elem ContextElement) {
 	state := vis.state
 	// The caller may have multiple contexts;
 	// perform the unification for each context.
 	for _, c := range vis.getContexts(arg) {
 		// For each context of the callee, match it with the caller context
-		// plus the call site. Unify the caller' arg and the callee's param
+		// plus the call's element. Unify the caller' arg and the callee's param
 		// for each matched context.
 		// For example, assume K=1,
 		//   func f(x, y *T) {
@@ -588,7 +737,7 @@ func (vis *visitor) unifyCallWithContexts(arg ssa.Value, param ssa.Value, callsi
 		// g() is called at contexts [g(x)] and [g(y)], so g.a is unified
 		// with f.x and f.y w.r.t. these contexts, resulting in two
 		// partitions {[g(x)]g.a, f.x} and {[g(y)]g.a, f.y}.
-		argCxt := append(*c, callsite)
+		argCxt := append(*c, elem)
 		argRef := MakeReference(c, arg)
 		for _, paramCxt := range vis.getContexts(param) {
 			if vis.contextKEqual(argCxt, *paramCxt) {
@@ -628,8 +777,13 @@ func (vis *visitor) visitBuiltin(builtin *ssa.Builtin, instr ssa.Instruction) {
 	}
 }
 
-// Handle some known functions, e.g. in package "fmt".
+// Handle some known functions, e.g. in package "fmt", and the functions
+// summarized in the configuration.
 func (vis *visitor) visitKnownFunction(fn *ssa.Function, instr ssa.Instruction) bool {
+	if summ := vis.config.FindSummary(utils.DecomposeFunction(fn)); summ != nil {
+		vis.visitSummarizedCall(summ, instr)
+		return true
+	}
 	// TODO(#312): Handle standard library functions.
 	// Add an operand reference to a field of the "dst", i.e. dst[index -> op].
 	addField := func(dst ssa.Value, op ssa.Value, index int) {
@@ -666,6 +820,70 @@ func (vis *visitor) visitKnownFunction(fn *ssa.Function, instr ssa.Instruction)
 	}
 }
 
+// Handle a call to a function summarized in the configuration.
+// Each tainted argument or return value takes the arguments that may taint it
+// as fields, indexed by argument position. For example, for summary
+// "{IfTainted: [1], TaintedRets: [0]}", call "t0 = f(a, b)" results in t0[1->b].
+func (vis *visitor) visitSummarizedCall(summ *config.FuncSummary, instr ssa.Instruction) {
+	common := instr.(ssa.CallInstruction).Common()
+	args := utils.CallArgs(common)
+
+	var dsts []ssa.Value
+	for _, i := range summ.TaintedArgs {
+		if i < len(args) {
+			dsts = append(dsts, args[i])
+		}
+	}
+	if call, ok := instr.(*ssa.Call); ok {
+		dsts = append(dsts, returnedValues(call, summ.TaintedRets)...)
+	}
+
+	for _, dst := range dsts {
+		if !mayShareObject(dst) {
+			continue
+		}
+		for _, i := range summ.IfTainted {
+			if i >= len(args) || !mayShareObject(args[i]) {
+				continue
+			}
+			fd := Field{Name: strconv.Itoa(i)}
+			for _, c := range vis.getContexts(dst) {
+				vis.unifyField(c, dst, fd, args[i])
+			}
+		}
+	}
+}
+
+// Return the values at the given return positions of a call.
+// For a single return value, this is the call itself; otherwise these are
+// the Extracts of the returned tuple, if they exist.
+func returnedValues(call *ssa.Call, positions []int) []ssa.Value {
+	var values []ssa.Value
+	if call.Call.Signature().Results().Len() == 1 {
+		for _, i := range positions {
+			if i == 0 {
+				values = append(values, call)
+			}
+		}
+		return values
+	}
+	if call.Referrers() == nil {
+		return nil
+	}
+	for _, r := range *call.Referrers() {
+		e, ok := r.(*ssa.Extract)
+		if !ok {
+			continue
+		}
+		for _, i := range positions {
+			if e.Index == i {
+				values = append(values, e)
+			}
+		}
+	}
+	return values
+}
+
 // Collect unification constraints corresponding to a call.
 // This generates constraints for unifying parameters, free variables, and return values.
 func (vis *visitor) collectCalleeConstraints(common *ssa.CallCommon, fn *ssa.Function, instr ssa.Instruction) (map[ssa.Value][]ssa.Value, map[ssa.Value][][]ssa.Value) {
@@ -675,7 +893,9 @@ func (vis *visitor) collectCalleeConstraints(common *ssa.CallCommon, fn *ssa.Fun
 	}
 	// TODO: in some rare cases, SSA may generate an imported function with no arguments while this function actually
 	//  takes arguments. Skip such functions here.
-	if len(fn.Params) != len(common.Args) {
+	// For an interface method call, the receiver is the first argument.
+	args := utils.CallArgs(common)
+	if len(fn.Params) != len(args) {
 		return nil, nil
 	}
 
@@ -683,8 +903,8 @@ func (vis *visitor) collectCalleeConstraints(common *ssa.CallCommon, fn *ssa.Fun
 	// Add caller_arg -> {callee_parameter} constraints for parameters.
 	// For example, for g(a, b) and func g(x, y), add <a, g.x> and
 	// <b, g.y> into the constraints.
-	for i := 0; i < len(common.Args); i++ {
-		arg, param := common.Args[i], fn.Params[i]
+	for i := 0; i < len(args); i++ {
+		arg, param := args[i], fn.Params[i]
 		if mayShareObject(arg) && typeMayShareObject(param.Type()) {
 			paramCstrs[arg] = append(paramCstrs[arg], param)
 		}
@@ -972,7 +1192,12 @@ func (vis *visitor) unifyByValue(ref1 Reference, ref2 Reference) {
 
 // Return all the calling contexts of the function to which a value belongs.
 func (vis *visitor) getContexts(v ssa.Value) []*Context {
-	if fn := v.Parent(); fn != nil {
+	return vis.functionContexts(v.Parent())
+}
+
+// Return all the calling contexts of a function.
+func (vis *visitor) functionContexts(fn *ssa.Function) []*Context {
+	if fn != nil {
 		if ctxs, ok := vis.contexts[fn]; ok {
 			return ctxs
 		}
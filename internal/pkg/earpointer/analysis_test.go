@@ -16,12 +16,15 @@ package earpointer_test
 
 import (
 	"fmt"
+	"go/types"
 	"sort"
 	"strconv"
 	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-flow-levee/internal/pkg/callees"
+	"github.com/google/go-flow-levee/internal/pkg/config"
 	"github.com/google/go-flow-levee/internal/pkg/earpointer"
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/buildssa"
@@ -30,6 +33,17 @@ import (
 
 // Compiles the code and then runs the EAR pointer analysis with a specific context K.
 func runCodeWithContext(code string, contextK int) (*earpointer.Partitions, error) {
+	earpointer.Analyzer.Flags.Set("useEAR", "true")
+	return runAnalyzer(code, earpointer.Analyzer, contextK)
+}
+
+// Compiles the code and then runs the EAR pointer analysis with a specific
+// kind of context and context K.
+func runCodeWithContextKind(code string, kind config.ContextKind, contextK int) (*earpointer.Partitions, error) {
+	return runAnalyzer(code, earpointer.NewAnalyzer(&config.Config{UseEAR: true, EARContext: kind}, callees.Analyzer), contextK)
+}
+
+func runAnalyzer(code string, analyzer *analysis.Analyzer, contextK int) (*earpointer.Partitions, error) {
 	pkg, err := buildSSA(code)
 	if err != nil {
 		return nil, fmt.Errorf("compilation failed: %s :\n %s", err, code)
@@ -42,11 +56,14 @@ func runCodeWithContext(code string, contextK int) (*earpointer.Partitions, erro
 		}
 	}
 	ssainput := buildssa.SSA{Pkg: pkg, SrcFuncs: srcFuncs}
-	pass := analysis.Pass{ResultOf: map[*analysis.Analyzer]interface{}{buildssa.Analyzer: &ssainput}}
-	earpointer.Analyzer.Flags.Set("useEAR", "true")
-	earpointer.Analyzer.Flags.Set("contextK", strconv.Itoa(contextK))
+	pass := analysis.Pass{
+		ResultOf:         map[*analysis.Analyzer]interface{}{buildssa.Analyzer: &ssainput},
+		AllObjectFacts:   func() []analysis.ObjectFact { return nil },
+		ExportObjectFact: func(types.Object, analysis.Fact) {},
+	}
+	analyzer.Flags.Set("contextK", strconv.Itoa(contextK))
 	// Run the analysis.
-	partitions, err := earpointer.Analyzer.Run(&pass)
+	partitions, err := analyzer.Run(&pass)
 	if err != nil {
 		return nil, fmt.Errorf("analyzer run failed: %v", ssainput)
 	}
@@ -68,6 +85,28 @@ func concat(m map[string]string) string {
 	return strings.Join(pstrs, ", ")
 }
 
+// Return the non-synthetic members of each partition, e.g. "{f.x,g.a}", sorted.
+// Unlike the field maps and the synthetic references, they do not depend on
+// the order in which the functions are analyzed.
+func partitionMembers(p *earpointer.Partitions) []string {
+	var pstrs []string
+	for rep := range p.Representatives() {
+		var mstrs []string
+		for _, m := range p.MembersForRep(rep) {
+			if _, ok := m.(earpointer.Synthetic); !ok {
+				mstrs = append(mstrs, m.String())
+			}
+		}
+		if len(mstrs) == 0 {
+			continue
+		}
+		sort.Strings(mstrs)
+		pstrs = append(pstrs, "{"+strings.Join(mstrs, ",")+"}")
+	}
+	sort.Strings(pstrs)
+	return pstrs
+}
+
 func TestFieldAddr(t *testing.T) {
 	code := `package p
 	type T struct { x *int; y *int }
@@ -1382,3 +1421,106 @@ func TestCallReturnContextSensitive(t *testing.T) {
 		t.Errorf("diff (-want +got):\n%s", diff)
 	}
 }
+
+func TestMethodCallObjectSensitive(t *testing.T) {
+	code := `package p
+	type Box struct {
+		v *int
+	}
+	func (b *Box) Put(v *int) {
+		b.v = v
+	}
+	func f(x, y *int) {
+		b1 := &Box{}
+		b2 := &Box{}
+		b1.Put(x)
+		b1.Put(x)
+		b2.Put(y)
+	}
+	`
+	/*
+		func f(x *int, y *int):
+		0:                                           entry P:0 S:0
+			t0 = new Box (complit)                   *Box
+			t1 = new Box (complit)                   *Box
+			t2 = (*Box).Put(t0, x)                   ()
+			t3 = (*Box).Put(t0, x)                   ()
+			t4 = (*Box).Put(t1, y)                   ()
+			return
+	*/
+	state, err := runCodeWithContextKind(code, config.ObjectContext, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Put() is called in one context per receiver, rather than per call site.
+	want := []string{
+		"{[f.t0]*Box:Put.b,f.t0}",
+		"{[f.t0]*Box:Put.t0}",
+		"{[f.t0]*Box:Put.v,f.x}",
+		"{[f.t1]*Box:Put.b,f.t1}",
+		"{[f.t1]*Box:Put.t0}",
+		"{[f.t1]*Box:Put.v,f.y}",
+	}
+	if diff := cmp.Diff(want, partitionMembers(state)); diff != "" {
+		t.Errorf("diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestMethodCallTypeSensitive(t *testing.T) {
+	code := `package p
+	type Box struct {
+		v *int
+	}
+	func (b *Box) Put(v *int) {
+		b.v = v
+	}
+	type Secret struct {
+		b Box
+	}
+	type Public struct {
+		b Box
+	}
+	func f(x, y *int) {
+		s1 := &Secret{}
+		s2 := &Secret{}
+		p := &Public{}
+		s1.b.Put(x)
+		s2.b.Put(x)
+		p.b.Put(y)
+	}
+	`
+	/*
+		func f(x *int, y *int):
+		0:                                           entry P:0 S:0
+			t0 = new Secret (complit)                *Secret
+			t1 = new Secret (complit)                *Secret
+			t2 = new Public (complit)                *Public
+			t3 = &t0.b [#0]                          *Box
+			t4 = (*Box).Put(t3, x)                   ()
+			t5 = &t1.b [#0]                          *Box
+			t6 = (*Box).Put(t5, x)                   ()
+			t7 = &t2.b [#0]                          *Box
+			t8 = (*Box).Put(t7, y)                   ()
+			return
+	*/
+	state, err := runCodeWithContextKind(code, config.TypeContext, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Put() is called in one context per type of the object holding the receiver:
+	// the boxes of the two secrets are unified, but not the box of the public.
+	want := []string{
+		"{[t.Public]*Box:Put.b,f.t7}",
+		"{[t.Public]*Box:Put.t0}",
+		"{[t.Public]*Box:Put.v,f.y}",
+		"{[t.Secret]*Box:Put.b,f.t3,f.t5}",
+		"{[t.Secret]*Box:Put.t0}",
+		"{[t.Secret]*Box:Put.v,f.x}",
+		"{f.t0}",
+		"{f.t1}",
+		"{f.t2}",
+	}
+	if diff := cmp.Diff(want, partitionMembers(state)); diff != "" {
+		t.Errorf("diff (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package earpointer
+
+import (
+	"fmt"
+	"go/types"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/google/go-flow-levee/internal/pkg/config"
+	"github.com/google/go-flow-levee/internal/pkg/fieldtags"
+	"github.com/google/go-flow-levee/internal/pkg/source"
+	infer "github.com/google/go-flow-levee/internal/pkg/sourceinfer"
+	"github.com/google/go-flow-levee/internal/pkg/sourcetype"
+	"github.com/google/go-flow-levee/internal/pkg/utils"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ssa"
+)
+
+// GlobalSources maps the globals that may hold a source, identified by
+// their package path and name (e.g. "example.com/cache.Entries"),
+// to the labels of the sources.
+type GlobalSources map[string][]string
+
+type globalSources struct {
+	Globals GlobalSources
+}
+
+func (*globalSources) AFact() {}
+
+func (g *globalSources) String() string {
+	var descs []string
+	for name, labels := range g.Globals {
+		descs = append(descs, fmt.Sprintf("%s holds sources labeled %q", name, labels))
+	}
+	sort.Strings(descs)
+	return strings.Join(descs, "; ")
+}
+
+// GlobalsAnalyzer reads its configuration from the file selected by the -config flag.
+var GlobalsAnalyzer = NewGlobalsAnalyzer(nil, Analyzer, source.Analyzer, fieldtags.Analyzer, infer.Analyzer)
+
+// NewGlobalsAnalyzer returns an analyzer bound to conf, which requires the
+// earPointer, sources, taggedFields and inferred analyzers bound to the same
+// configuration. If conf is nil, the analyzer reads its configuration as
+// GlobalsAnalyzer does.
+func NewGlobalsAnalyzer(conf *config.Config, earPointer, sources, taggedFields, inferred *analysis.Analyzer) *analysis.Analyzer {
+	// The analyzer shares the -engine flag of earPointer.
+	engine := EngineFlag(earPointer)
+	a := &analysis.Analyzer{
+		Name: "earglobals",
+		Doc: `This analyzer finds the globals that may hold a source, using the EAR pointer analysis.
+
+The globals are exported as facts, so that a source stored in a global
+by a package is known to the packages importing it. The result holds
+the globals of the dependencies of the analyzed package.`,
+		Flags: config.NewFlagSet(conf),
+		Run: func(pass *analysis.Pass) (interface{}, error) {
+			heap := pass.ResultOf[earPointer].(*Partitions)
+			funcSources := pass.ResultOf[sources].(source.ResultType)
+			taggedFields := pass.ResultOf[taggedFields].(fieldtags.ResultType)
+			inferredSources := pass.ResultOf[inferred].(infer.ResultType)
+			return runGlobals(pass, conf, *engine, heap, funcSources, taggedFields, inferredSources)
+		},
+		Requires:   []*analysis.Analyzer{earPointer, sources, taggedFields, inferred},
+		ResultType: reflect.TypeOf(new(GlobalSources)).Elem(),
+		FactTypes:  []analysis.Fact{new(globalSources)},
+	}
+	a.Flags.Var(engine, "engine", earPointer.Flags.Lookup("engine").Usage)
+	return a
+}
+
+func runGlobals(pass *analysis.Pass, conf *config.Config, engine config.Engine, heap *Partitions, funcSources source.ResultType,
+	taggedFields fieldtags.ResultType, inferredSources infer.ResultType) (interface{}, error) {
+
+	conf, err := config.Load(conf, pass.Pkg.Path())
+	if err != nil {
+		return nil, err
+	}
+	imported := GlobalSources{}
+	if _, ear := conf.Engines(engine); !ear || isStandardLibrary(pass) {
+		return imported, nil
+	}
+	for _, f := range pass.AllPackageFacts() {
+		for name, labels := range f.Fact.(*globalSources).Globals {
+			imported[name] = union(imported[name], labels)
+		}
+	}
+	// A global of this package may hold a source stored in a global of another package.
+	funcSources = AddGlobalSources(funcSources, imported, heap)
+	held := GlobalSources{}
+	for name, labels := range taintedGlobals(funcSources, TaintFields(conf, taggedFields, inferredSources), heap, conf) {
+		if _, ok := imported[name]; !ok {
+			held[name] = labels
+		}
+	}
+	if len(held) > 0 {
+		pass.ExportPackageFact(&globalSources{Globals: held})
+	}
+	return imported, nil
+}
+
+func union(labels, others []string) []string {
+	for _, l := range others {
+		if !containsLabel(labels, l) {
+			labels = append(labels, l)
+		}
+	}
+	return labels
+}
+
+// TaintFields returns a function reporting whether a field of a struct is tainted.
+// Fields holding a value of an inferred source type are tainted, since
+// they are what makes the struct holding them a source.
+func TaintFields(conf *config.Config, taggedFields fieldtags.ResultType, inferredSources infer.ResultType) func(named *types.Named, index int) bool {
+	return func(named *types.Named, index int) bool {
+		if tt, ok := named.Underlying().(*types.Struct); ok {
+			return conf.IsSourceField(utils.DecomposeField(named, index)) || taggedFields.IsSourceField(tt, index) ||
+				(inferredSources.IsSourceType(named) && sourcetype.IsSourceType(conf, taggedFields, inferredSources, tt.Field(index).Type()))
+		}
+		return false
+	}
+}
+
+// Return the globals that may hold a source, along with the labels of the sources.
+func taintedGlobals(funcSources source.ResultType, isTaintField func(named *types.Named, index int) bool,
+	heap *Partitions, conf *config.Config) GlobalSources {
+
+	var globals []Reference
+	for ref := range heap.References() {
+		if _, ok := ref.(Global); ok {
+			globals = append(globals, ref)
+		}
+	}
+	result := GlobalSources{}
+	for fn, sources := range funcSources {
+		reachable := make(map[*ssa.Function]bool)
+		boundedReachableFunctions(fn, heap.cg, conf.EARTaintCallSpan, reachable)
+		srcRefs := make(map[*source.Source]ReferenceSet)
+		for _, src := range sources {
+			srcRefs[src] = srcAliasRefs(src, isTaintField, heap, reachable, conf)
+		}
+		for _, g := range globals {
+			// The references held by the global, within the reachable functions.
+			ht := &heapTraversal{heap: heap, reachableFns: reachable, visited: make(ReferenceSet)}
+			heldRefs := make(ReferenceSet)
+			ht.fieldRefs(g, heldRefs)
+			for _, src := range sources {
+				for ref := range srcRefs[src] {
+					if heldRefs[ref] {
+						name := globalName(g.(Global).global)
+						result[name] = union(result[name], src.Labels)
+						break
+					}
+				}
+			}
+		}
+	}
+	return result
+}
+
+// AddGlobalSources returns the sources of each function, along with the
+// globals of other packages that may hold a source and that the function
+// refers to. A global is a single source shared by the functions referring to it.
+func AddGlobalSources(funcSources source.ResultType, globals GlobalSources, heap *Partitions) source.ResultType {
+	if len(globals) == 0 {
+		return funcSources
+	}
+	result := make(source.ResultType, len(funcSources))
+	for fn, sources := range funcSources {
+		result[fn] = append([]*source.Source(nil), sources...)
+	}
+	globalSrcs := make(map[*ssa.Global]*source.Source)
+	var operands []*ssa.Value
+	for fn := range heap.cg.Nodes {
+		if fn == nil || fn.Pkg == nil {
+			continue
+		}
+		for _, b := range fn.Blocks {
+			for _, instr := range b.Instrs {
+				for _, op := range instr.Operands(operands[:0]) {
+					g, ok := (*op).(*ssa.Global)
+					if !ok || g.Pkg == fn.Pkg {
+						continue
+					}
+					labels, ok := globals[globalName(g)]
+					if !ok {
+						continue
+					}
+					src, ok := globalSrcs[g]
+					if !ok {
+						src = source.New(g, labels)
+						globalSrcs[g] = src
+					}
+					if !containsSource(result[fn], src) {
+						result[fn] = append(result[fn], src)
+					}
+				}
+			}
+		}
+	}
+	return result
+}
+
+func containsSource(sources []*source.Source, src *source.Source) bool {
+	for _, s := range sources {
+		if s == src {
+			return true
+		}
+	}
+	return false
+}
@@ -36,7 +36,14 @@ type Reference interface {
 
 // Context represents the calling context of a reference.
 // Typically, it contains a stack of call instructions.
-type Context []ssa.Instruction
+type Context []ContextElement
+
+// A ContextElement distinguishes the calls to a function, depending on
+// the kind of context sensitivity: it is a call instruction, the allocation
+// site of the receiver of a method, or the type of the object allocated there.
+type ContextElement interface {
+	String() string
+}
 
 // Local is a heap partition that represents all abstract
 // objects that a register of reference type can point to in a specific context.
@@ -112,6 +119,8 @@ const (
 type Synthetic struct {
 	kind SyntheticKind
 	ref  Reference
+	// The name of the field of a SyntheticField reference, if it is known.
+	field string
 }
 
 func (s Synthetic) Type() types.Type {
@@ -126,7 +135,7 @@ func (s Synthetic) String() string {
 	if s.kind == SyntheticValueOf {
 		return "*" + s.ref.String()
 	}
-	return s.ref.String() + "[.]"
+	return s.ref.String() + "[." + s.field + "]"
 }
 
 // Field can be (1) a struct field linked to an IR field (Var);
@@ -184,6 +193,12 @@ func MakeSynthetic(kind SyntheticKind, ref Reference) Synthetic {
 	return Synthetic{kind: kind, ref: ref}
 }
 
+// Constructs the synthetic reference of the field with a given name
+// of a reference, which differs from those of its other fields.
+func MakeSyntheticField(ref Reference, name string) Synthetic {
+	return Synthetic{kind: SyntheticField, ref: ref, field: name}
+}
+
 // Returns whether a value of this type may share an object pointed by other
 // values. It is used for identifying copy-by-reference objects. For example, it
 // returns false for any integer type, and returns true for pointer type and
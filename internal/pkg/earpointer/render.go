@@ -0,0 +1,175 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package earpointer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/go-flow-levee/internal/pkg/utils"
+	"golang.org/x/tools/go/ssa"
+)
+
+// A partition of the heap, as rendered in DOT or JSON.
+type renderedPartition struct {
+	ID      string   `json:"id"`
+	Members []string `json:"members"`
+	// The partitions pointed to by the fields of this partition, by field name.
+	// The direct points-to relation is named "->".
+	Fields map[string]string `json:"fields,omitempty"`
+	// The members that are the source, or an argument of the sink, of a trace.
+	Sources []string `json:"sources,omitempty"`
+	Sinks   []string `json:"sinks,omitempty"`
+}
+
+// DOT produces DOT source code representing the partitions as nodes, and
+// their field maps as edges labeled with the field names. If trace is not nil,
+// the partitions holding its source and the arguments of its sink are highlighted.
+func (p *Partitions) DOT(trace *SourceSinkTrace) string {
+	var b strings.Builder
+	b.WriteString("digraph {\n")
+	partitions := p.render(trace)
+	for _, part := range partitions {
+		marks := make(map[string]string)
+		for _, m := range part.Sources {
+			marks[m] += " (source)"
+		}
+		for _, m := range part.Sinks {
+			marks[m] += " (sink)"
+		}
+		labels := make([]string, len(part.Members))
+		for i, m := range part.Members {
+			labels[i] = m + marks[m]
+		}
+		b.WriteString(fmt.Sprintf("\t%s [shape=box, label=%q%s];\n", part.ID, strings.Join(labels, "\n"), highlight(part)))
+	}
+	for _, part := range partitions {
+		var names []string
+		for name := range part.Fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			b.WriteString(fmt.Sprintf("\t%s -> %s [label=%q];\n", part.ID, part.Fields[name], name))
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Return the DOT attributes highlighting a partition holding a source (red),
+// a sink's argument (blue), or both (purple).
+func highlight(part *renderedPartition) string {
+	switch {
+	case len(part.Sources) > 0 && len(part.Sinks) > 0:
+		return ", color=purple, style=bold"
+	case len(part.Sources) > 0:
+		return ", color=red, style=bold"
+	case len(part.Sinks) > 0:
+		return ", color=blue, style=bold"
+	}
+	return ""
+}
+
+// JSON produces a JSON document holding the partitions under "partitions",
+// each with its members, its fields, and, if trace is not nil, the members
+// that are the trace's source or an argument of its sink.
+func (p *Partitions) JSON(trace *SourceSinkTrace) ([]byte, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	// Keep the direct points-to field readable as "->".
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	err := enc.Encode(struct {
+		Partitions []*renderedPartition `json:"partitions"`
+	}{p.render(trace)})
+	return b.Bytes(), err
+}
+
+// Return the partitions ordered by their members,
+// which are named "p0", "p1", etc. in this order.
+func (p *Partitions) render(trace *SourceSinkTrace) []*renderedPartition {
+	var srcValues, sinkValues []ssa.Value
+	if trace != nil {
+		if v, ok := trace.Src.Node.(ssa.Value); ok {
+			srcValues = append(srcValues, v)
+		}
+		sinkValues = sinkArgs(trace.Sink)
+	}
+	byRep := make(map[Reference]*renderedPartition)
+	var partitions []*renderedPartition
+	for rep := range p.Representatives() {
+		part := &renderedPartition{}
+		for _, m := range p.MembersForRep(rep) {
+			part.Members = append(part.Members, m.String())
+			// Synthetic references only stand for what their value refers to.
+			if _, ok := m.(Synthetic); ok {
+				continue
+			}
+			if containsValue(srcValues, m.Value()) {
+				part.Sources = append(part.Sources, m.String())
+			}
+			if containsValue(sinkValues, m.Value()) {
+				part.Sinks = append(part.Sinks, m.String())
+			}
+		}
+		sort.Strings(part.Members)
+		sort.Strings(part.Sources)
+		sort.Strings(part.Sinks)
+		byRep[rep] = part
+		partitions = append(partitions, part)
+	}
+	sort.Slice(partitions, func(i, j int) bool {
+		return strings.Join(partitions[i].Members, ",") < strings.Join(partitions[j].Members, ",")
+	})
+	for i, part := range partitions {
+		part.ID = fmt.Sprintf("p%d", i)
+	}
+	for rep, part := range byRep {
+		for fd, ref := range p.PartitionFieldMap(rep) {
+			if part.Fields == nil {
+				part.Fields = make(map[string]string)
+			}
+			part.Fields[fd.Name] = byRep[p.Representative(ref)].ID
+		}
+	}
+	return partitions
+}
+
+// Return the arguments of a sink: those of a call, or the operands of another instruction.
+func sinkArgs(sink ssa.Instruction) []ssa.Value {
+	if call, ok := sink.(ssa.CallInstruction); ok {
+		return utils.CallArgs(call.Common())
+	}
+	var values []ssa.Value
+	for _, op := range sink.Operands(nil) {
+		if *op != nil {
+			values = append(values, *op)
+		}
+	}
+	return values
+}
+
+func containsValue(values []ssa.Value, v ssa.Value) bool {
+	for _, w := range values {
+		if w == v {
+			return true
+		}
+	}
+	return false
+}
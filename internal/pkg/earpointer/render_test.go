@@ -0,0 +1,153 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package earpointer_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-flow-levee/internal/pkg/earpointer"
+	"github.com/google/go-flow-levee/internal/pkg/source"
+	"golang.org/x/tools/go/ssa"
+)
+
+const renderCode = `package p
+	type T struct {
+		x *int
+	}
+	func sink(t *T) {}
+	func f(s *int, t *T) {
+		t.x = s
+		sink(t)
+	}
+	`
+
+// Return a trace from the first parameter of f to its call to sink.
+func renderTrace(t *testing.T, state *earpointer.Partitions) *earpointer.SourceSinkTrace {
+	for ref := range state.References() {
+		fn := ref.Value().Parent()
+		if fn == nil || fn.Name() != "f" {
+			continue
+		}
+		for _, b := range fn.Blocks {
+			for _, instr := range b.Instrs {
+				if call, ok := instr.(*ssa.Call); ok {
+					return &earpointer.SourceSinkTrace{Src: source.New(fn.Params[0], nil), Sink: call}
+				}
+			}
+		}
+	}
+	t.Fatal("no call in f")
+	return nil
+}
+
+func TestDOT(t *testing.T) {
+	state, err := runCodeK0(renderCode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `digraph {
+	p0 [shape=box, label="*f.t"];
+	p1 [shape=box, label="f.s (source)", color=red, style=bold];
+	p2 [shape=box, label="f.t (sink)\nsink.t", color=blue, style=bold];
+	p3 [shape=box, label="f.t0"];
+	p0 -> p3 [label="x"];
+	p2 -> p0 [label="->"];
+	p3 -> p1 [label="->"];
+}
+`
+	if diff := cmp.Diff(want, state.DOT(renderTrace(t, state))); diff != "" {
+		t.Errorf("diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestDOTWithoutTrace(t *testing.T) {
+	state, err := runCodeK0(renderCode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `digraph {
+	p0 [shape=box, label="*f.t"];
+	p1 [shape=box, label="f.s"];
+	p2 [shape=box, label="f.t\nsink.t"];
+	p3 [shape=box, label="f.t0"];
+	p0 -> p3 [label="x"];
+	p2 -> p0 [label="->"];
+	p3 -> p1 [label="->"];
+}
+`
+	if diff := cmp.Diff(want, state.DOT(nil)); diff != "" {
+		t.Errorf("diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	state, err := runCodeK0(renderCode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := state.JSON(renderTrace(t, state))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "partitions": [
+    {
+      "id": "p0",
+      "members": [
+        "*f.t"
+      ],
+      "fields": {
+        "x": "p3"
+      }
+    },
+    {
+      "id": "p1",
+      "members": [
+        "f.s"
+      ],
+      "sources": [
+        "f.s"
+      ]
+    },
+    {
+      "id": "p2",
+      "members": [
+        "f.t",
+        "sink.t"
+      ],
+      "fields": {
+        "->": "p0"
+      },
+      "sinks": [
+        "f.t"
+      ]
+    },
+    {
+      "id": "p3",
+      "members": [
+        "f.t0"
+      ],
+      "fields": {
+        "->": "p1"
+      }
+    }
+  ]
+}
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("diff (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package earpointer
+
+import (
+	"go/token"
+	"go/types"
+
+	"github.com/google/go-flow-levee/internal/pkg/config"
+	"github.com/google/go-flow-levee/internal/pkg/sanitizer"
+	"github.com/google/go-flow-levee/internal/pkg/source"
+	"github.com/google/go-flow-levee/internal/pkg/utils"
+	"golang.org/x/tools/go/ssa"
+)
+
+// A call to a sanitizer or validator, along with the references it cleans.
+type sanitization struct {
+	call *ssa.Call
+	// Determines the instructions that can only be executed after the call
+	// removed the taint: those dominated by a sanitizer call, or by the
+	// successful outcome of a validator call.
+	sanitizer.Dominator
+	// Whether the called functions remove the taint with a given label.
+	isSanitizerFor func(label string) bool
+	// The references of the returned value. These are clean wherever the
+	// taint reaching them must go through the call, or through other
+	// sanitizers of the same references.
+	retRefs ReferenceSet
+	// The references of the arguments that are sanitized in place.
+	// These are clean at the instructions the Dominator dominates.
+	argRefs ReferenceSet
+}
+
+// Obtain the calls to sanitizers and validators within the reachable functions.
+// A call sanitizes a value only if all of its callees are sanitizers,
+// and validates it only if all of its callees are validators.
+func collectSanitizations(heap *Partitions, reachable map[*ssa.Function]bool,
+	calleeMap map[*ssa.CallCommon][]*ssa.Function, conf *config.Config) []*sanitization {
+
+	var result []*sanitization
+	for fn := range reachable {
+		for _, b := range fn.Blocks {
+			for _, instr := range b.Instrs {
+				call, ok := instr.(*ssa.Call)
+				if !ok {
+					continue
+				}
+				callees := calleeMap[call.Common()]
+				if len(callees) == 0 {
+					continue
+				}
+				switch {
+				case allCallees(callees, conf.IsSanitizer):
+					retRefs := make(ReferenceSet)
+					if t, ok := call.Type().(*types.Tuple); !ok || t.Len() > 0 {
+						retHT := &heapTraversal{heap: heap, reachableFns: reachable, visited: make(ReferenceSet)}
+						retHT.fieldRefs(MakeLocalWithEmptyContext(call), retRefs)
+					}
+					result = append(result, &sanitization{
+						call:           call,
+						Dominator:      sanitizer.Sanitizer{Call: call},
+						isSanitizerFor: labelMatcher(callees, conf.IsSanitizerForLabel),
+						retRefs:        retRefs,
+						argRefs:        argRefs(heap, reachable, call, inPlaceArg(callees, conf)),
+					})
+				case allCallees(callees, conf.IsValidator):
+					// A validator's result does not carry its arguments,
+					// which are only clean where its check succeeded.
+					v, ok := sanitizer.NewValidator(call)
+					if !ok {
+						continue
+					}
+					result = append(result, &sanitization{
+						call:           call,
+						Dominator:      v,
+						isSanitizerFor: labelMatcher(callees, conf.IsValidatorForLabel),
+						retRefs:        make(ReferenceSet),
+						argRefs:        argRefs(heap, reachable, call, func(int) bool { return true }),
+					})
+				}
+			}
+		}
+	}
+	return result
+}
+
+// Obtain the references of the arguments of a call at the positions
+// accepted by "include". These include the references of their aliases.
+func argRefs(heap *Partitions, reachable map[*ssa.Function]bool, call *ssa.Call, include func(pos int) bool) ReferenceSet {
+	refs := make(ReferenceSet)
+	ht := &heapTraversal{heap: heap, reachableFns: reachable, visited: make(ReferenceSet)}
+	for pos, a := range utils.CallArgs(call.Common()) {
+		if !include(pos) {
+			continue
+		}
+		if isLocal(a) || isGlobal(a) {
+			ht.fieldRefs(MakeLocalWithEmptyContext(a), refs)
+		}
+	}
+	return refs
+}
+
+// Return a function reporting whether all the callees sanitize
+// the argument at a given position in place.
+func inPlaceArg(callees []*ssa.Function, conf *config.Config) func(int) bool {
+	return func(pos int) bool {
+		for _, callee := range callees {
+			path, recv, name := utils.DecomposeFunction(callee)
+			if !conf.IsInPlaceSanitizerArg(path, recv, name, pos) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Return a function reporting whether all the callees match a given label.
+func labelMatcher(callees []*ssa.Function, match func(path, recv, name, label string) bool) func(string) bool {
+	return func(label string) bool {
+		for _, callee := range callees {
+			path, recv, name := utils.DecomposeFunction(callee)
+			if !match(path, recv, name, label) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func allCallees(callees []*ssa.Function, match func(path, recv, name string) bool) bool {
+	for _, callee := range callees {
+		if !match(utils.DecomposeFunction(callee)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Return whether the taint with a given label that reaches reference "ref"
+// from a source is removed by sanitizers before reaching a sink.
+// Several sanitizers remove the taint together if every path from the source
+// to the sink goes through one of them, e.g. when the reference is sanitized
+// separately in each branch of an if statement.
+func (ht *heapTraversal) isSanitized(ref Reference, src *source.Source, sink ssa.Instruction, label string) bool {
+	var sanitizers []sanitizer.Dominator
+	for _, s := range ht.sanitizations {
+		if !s.isSanitizerFor(label) {
+			continue
+		}
+		// The source is obtained from the sanitized value, e.g. through
+		// a type assertion on the value returned by the sanitizer.
+		if v, ok := src.Node.(ssa.Value); ok && producingCall(v) == s.call {
+			return true
+		}
+		if s.argRefs[ref] && s.Dominates(sink) {
+			return true
+		}
+		if s.retRefs[ref] || s.argRefs[ref] {
+			sanitizers = append(sanitizers, s.Dominator)
+		}
+	}
+	if len(sanitizers) == 0 {
+		return false
+	}
+	// When the source is not an instruction of the sink's function,
+	// the paths start at the function's entry.
+	from, _ := src.Node.(ssa.Instruction)
+	return sanitizer.Covers(sanitizers, from, sink)
+}
+
+// Return the call producing a value, looking through the operations that
+// extract a part of a value or change its type; return nil if there is none.
+func producingCall(v ssa.Value) *ssa.Call {
+	for {
+		switch t := v.(type) {
+		case *ssa.Call:
+			return t
+		case *ssa.Extract:
+			v = t.Tuple
+		case *ssa.TypeAssert:
+			v = t.X
+		case *ssa.ChangeInterface:
+			v = t.X
+		case *ssa.ChangeType:
+			v = t.X
+		case *ssa.Field:
+			v = t.X
+		case *ssa.FieldAddr:
+			v = t.X
+		case *ssa.Index:
+			v = t.X
+		case *ssa.IndexAddr:
+			v = t.X
+		case *ssa.UnOp:
+			if t.Op != token.MUL {
+				return nil
+			}
+			v = t.X
+		default:
+			return nil
+		}
+	}
+}
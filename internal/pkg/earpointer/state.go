@@ -19,11 +19,13 @@
 package earpointer
 
 import (
+	"go/types"
 	"log"
 	"sort"
 	"strings"
 
 	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/ssa"
 )
 
 // parentMap maps a reference to its representative (i.e. the parent
@@ -301,6 +303,10 @@ type Partitions struct {
 
 	// The call graph used to unify callers and callees.
 	cg *callgraph.Graph
+	// The callee functions at each callsite, according to the call graph.
+	callees map[*ssa.CallCommon][]*ssa.Function
+	// The summaries of the functions of other packages.
+	summaries map[types.Object]*heapSummary
 }
 
 func (state *state) ToPartitions() *Partitions {
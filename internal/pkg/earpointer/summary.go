@@ -0,0 +1,429 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package earpointer
+
+import (
+	"fmt"
+	"go/types"
+	"sort"
+	"strings"
+
+	"github.com/google/go-flow-levee/internal/pkg/config"
+	"github.com/google/go-flow-levee/internal/pkg/source"
+	"github.com/google/go-flow-levee/internal/pkg/utils"
+	"golang.org/x/tools/go/ssa"
+)
+
+// A heapSummary summarizes the effects of a function on the EAR heap
+// that are visible to its callers. Summaries are exported as facts,
+// so that the unification carries across package boundaries:
+// the callers in other packages do not have the function's body.
+type heapSummary struct {
+	// Aliases are the groups of access paths that belong to the same
+	// partition when the function returns.
+	Aliases [][]accessPath
+	// SinkParams are the parameters whose argument's taint reaches a sink
+	// within the functions called by the function, ordered by position,
+	// so that the encoding of the summary is deterministic.
+	SinkParams []sinkParam
+}
+
+// A sinkParam is a parameter whose argument's taint reaches a sink with the
+// given labels. For a method, the receiver is the first parameter.
+type sinkParam struct {
+	Pos    int
+	Labels []string
+}
+
+func (*heapSummary) AFact() {}
+
+// sinkLabels returns the labels with which the taint of the argument
+// of the parameter at a position reaches a sink, if any.
+func (s *heapSummary) sinkLabels(pos int) []string {
+	i := sort.Search(len(s.SinkParams), func(i int) bool { return s.SinkParams[i].Pos >= pos })
+	if i < len(s.SinkParams) && s.SinkParams[i].Pos == pos {
+		return s.SinkParams[i].Labels
+	}
+	return nil
+}
+
+func (s *heapSummary) String() string {
+	var descs []string
+	for _, group := range s.Aliases {
+		var paths []string
+		for _, p := range group {
+			paths = append(paths, p.String())
+		}
+		descs = append(descs, "aliases "+strings.Join(paths, " "))
+	}
+	for _, p := range s.SinkParams {
+		descs = append(descs, fmt.Sprintf("param %d reaches a sink", p.Pos))
+	}
+	return strings.Join(descs, "; ")
+}
+
+// pathKind is the kind of the root of an access path.
+type pathKind int
+
+const (
+	paramPath pathKind = iota
+	resultPath
+	globalPath
+)
+
+// An accessPath identifies a partition by a root that is visible to the
+// callers of a function, and by the names of the fields leading from the
+// root's partition to the partition. For example, in method
+// "func (b *Buffer) Write(s *Source) { b.src = s }", the partition of s is
+// identified by "p0[->][src][->]" and by "p1".
+type accessPath struct {
+	Kind pathKind
+	// Index is the position of a parameter or result root.
+	Index int
+	// Global is the name of a global root, as given by globalName.
+	Global string
+	Fields []string
+}
+
+func (p accessPath) String() string {
+	var s string
+	switch p.Kind {
+	case paramPath:
+		s = fmt.Sprintf("p%d", p.Index)
+	case resultPath:
+		s = fmt.Sprintf("r%d", p.Index)
+	case globalPath:
+		s = p.Global
+	}
+	for _, f := range p.Fields {
+		s += "[" + f + "]"
+	}
+	return s
+}
+
+// The maximum number of fields in an access path.
+const maxAccessPathFields = 4
+
+// globalName returns the name identifying a global across packages,
+// e.g. "example.com/cache.Entries".
+func globalName(g *ssa.Global) string {
+	return g.Pkg.Pkg.Path() + "." + g.Name()
+}
+
+// Summarize the functions of a package that may be called from other packages.
+// The functions that are sinks, sanitizers or excluded are not summarized,
+// as their calls are handled according to the configuration.
+func summarize(pkg *ssa.Package, fns []*ssa.Function, heap *Partitions, conf *config.Config) map[*ssa.Function]*heapSummary {
+	var globals []*ssa.Global
+	for ref := range heap.References() {
+		if g, ok := ref.(Global); ok {
+			globals = append(globals, g.global)
+		}
+	}
+	sort.Slice(globals, func(i, j int) bool { return globalName(globals[i]) < globalName(globals[j]) })
+
+	summaries := make(map[*ssa.Function]*heapSummary)
+	for _, fn := range fns {
+		if fn.Object() == nil || fn.Pkg != pkg {
+			continue
+		}
+		path, recv, name := utils.DecomposeFunction(fn)
+		if conf.IsSink(path, recv, name) || conf.IsSanitizer(path, recv, name) || conf.IsExcluded(path, recv, name) {
+			continue
+		}
+		s := &heapSummary{
+			Aliases:    aliases(fn, globals, heap),
+			SinkParams: sinkParams(fn, heap, conf),
+		}
+		if len(s.Aliases) > 0 || len(s.SinkParams) > 0 {
+			summaries[fn] = s
+		}
+	}
+	return summaries
+}
+
+// Group the access paths rooted at the parameters and results of a function,
+// and at globals, by the partition they identify. Only the groups involving
+// the function's parameters or results are returned.
+func aliases(fn *ssa.Function, globals []*ssa.Global, heap *Partitions) [][]accessPath {
+	paths := make(map[Reference][]accessPath)
+	var order []Reference
+	var walk func(ref Reference, p accessPath)
+	walk = func(ref Reference, p accessPath) {
+		if !heap.Has(ref) {
+			return
+		}
+		rep := heap.Representative(ref)
+		for _, q := range paths[rep] {
+			if q.Kind == p.Kind && q.Index == p.Index && q.Global == p.Global {
+				// The partition is already identified through the same root.
+				return
+			}
+		}
+		if len(paths[rep]) == 0 {
+			order = append(order, rep)
+		}
+		paths[rep] = append(paths[rep], p)
+		if len(p.Fields) == maxAccessPathFields {
+			return
+		}
+		fmap := heap.PartitionFieldMap(rep)
+		var fields []Field
+		for fd := range fmap {
+			fields = append(fields, fd)
+		}
+		sort.Slice(fields, func(i, j int) bool { return fields[i].Name < fields[j].Name })
+		for _, fd := range fields {
+			next := p
+			next.Fields = append(append([]string(nil), p.Fields...), fd.Name)
+			walk(fmap[fd], next)
+		}
+	}
+
+	for i, param := range fn.Params {
+		walk(MakeLocalWithEmptyContext(param), accessPath{Kind: paramPath, Index: i})
+	}
+	for _, b := range fn.Blocks {
+		if ret, ok := b.Instrs[len(b.Instrs)-1].(*ssa.Return); ok {
+			for i, r := range ret.Results {
+				walk(MakeLocalWithEmptyContext(r), accessPath{Kind: resultPath, Index: i})
+			}
+		}
+	}
+	for _, g := range globals {
+		walk(MakeGlobal(g), accessPath{Kind: globalPath, Global: globalName(g)})
+	}
+
+	var groups [][]accessPath
+	for _, rep := range order {
+		group := paths[rep]
+		// The aliases between globals do not depend on the function.
+		if len(group) > 1 && group[0].Kind != globalPath {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// Return the labels with which the taint of each parameter of a function
+// reaches a sink within the functions it calls, up to EARTaintCallSpan,
+// ordered by the position of the parameter.
+func sinkParams(fn *ssa.Function, heap *Partitions, conf *config.Config) []sinkParam {
+	reachable := make(map[*ssa.Function]bool)
+	boundedCallees(fn, heap, conf.EARTaintCallSpan, reachable)
+	ht := &heapTraversal{heap: heap, reachableFns: reachable, visited: make(ReferenceSet)}
+	// Each parameter acts as a source with every label, and is tainted as a whole.
+	var sources []*source.Source
+	srcRefs := make(map[*source.Source]ReferenceSet)
+	positions := make(map[*source.Source]int)
+	for i, param := range fn.Params {
+		ref := MakeLocalWithEmptyContext(param)
+		if !heap.Has(ref) {
+			continue
+		}
+		src := source.New(param, conf.Labels())
+		srcRefs[src] = make(ReferenceSet)
+		ht.fieldRefs(ref, srcRefs[src])
+		sources = append(sources, src)
+		positions[src] = i
+	}
+	if len(sources) == 0 {
+		return nil
+	}
+
+	calleeMap := heap.callees
+	ht = &heapTraversal{heap: heap, reachableFns: reachable, visited: make(ReferenceSet),
+		sanitizations: collectSanitizations(heap, reachable, calleeMap, conf)}
+	tc := &traceCollector{cg: heap.cg, reachable: reachable, seen: make(map[sourceSink]bool)}
+	ht.traceSinks(sources, srcRefs, calleeMap, conf, tc)
+	if len(tc.traces) == 0 {
+		return nil
+	}
+	labels := make(map[int][]string)
+	for _, t := range tc.traces {
+		i := positions[t.Src]
+		for _, l := range t.Labels {
+			if !containsLabel(labels[i], l) {
+				labels[i] = append(labels[i], l)
+			}
+		}
+	}
+	var params []sinkParam
+	for i, ls := range labels {
+		sort.Strings(ls)
+		params = append(params, sinkParam{Pos: i, Labels: ls})
+	}
+	sort.Slice(params, func(i, j int) bool { return params[i].Pos < params[j].Pos })
+	return params
+}
+
+func containsLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// For a function, transitively get the functions it may call according to
+// the call graph, within a bounded call depth. Unlike boundedReachableFunctions,
+// the callers are not considered.
+func boundedCallees(fn *ssa.Function, heap *Partitions, depth uint, result map[*ssa.Function]bool) {
+	if depth <= 0 || result[fn] {
+		return
+	}
+	result[fn] = true
+	node := heap.cg.Nodes[fn]
+	if node == nil {
+		return
+	}
+	for _, out := range node.Out {
+		boundedCallees(out.Callee.Func, heap, depth-1, result)
+	}
+}
+
+// Handle a call to a function of another package, whose body is unavailable,
+// using its summary: the references identified by each group of aliased
+// access paths are unified in the caller.
+func (vis *visitor) visitHeapSummary(summ *heapSummary, fn *ssa.Function, instr ssa.Instruction) {
+	args := utils.CallArgs(instr.(ssa.CallInstruction).Common())
+	// For an interface method call, the receiver is the first argument.
+	params := fn.Signature.Params().Len()
+	if fn.Signature.Recv() != nil {
+		params++
+	}
+	if len(args) != params {
+		return
+	}
+	for _, c := range vis.functionContexts(instr.Parent()) {
+		for _, group := range summ.Aliases {
+			var refs []Reference
+			for _, p := range group {
+				refs = append(refs, vis.resolveAccessPath(c, p, args, instr)...)
+			}
+			for i := 1; i < len(refs); i++ {
+				vis.state.Unify(refs[0], refs[i])
+			}
+		}
+	}
+}
+
+// Return the references identified by an access path at a call site in a
+// given context. The references of the fields along the path are synthesized
+// if they do not exist. Where the type of a reference along the path is known,
+// its fields are those of the IR, so that the path leads to the same references
+// as the accesses to these fields in the caller.
+func (vis *visitor) resolveAccessPath(c *Context, p accessPath, args []ssa.Value, instr ssa.Instruction) []Reference {
+	var roots []ssa.Value
+	switch p.Kind {
+	case paramPath:
+		roots = []ssa.Value{args[p.Index]}
+	case resultPath:
+		if call, ok := instr.(*ssa.Call); ok {
+			roots = returnedValues(call, []int{p.Index})
+		}
+	case globalPath:
+		if g := lookupGlobal(instr.Parent().Prog, p.Global); g != nil {
+			roots = []ssa.Value{g}
+		}
+	}
+	state := vis.state
+	var refs []Reference
+	for _, root := range roots {
+		if !mayShareObject(root) {
+			continue
+		}
+		ref := state.Insert(MakeReference(c, root))
+		tp := root.Type()
+		for _, name := range p.Fields {
+			if name == directPointToField.Name {
+				ref = vis.getPointee(ref)
+				if pt, ok := tp.(*types.Pointer); ok {
+					tp = pt.Elem()
+				}
+				continue
+			}
+			fd := fieldByName(tp, name)
+			tp = nil
+			if fd.irField != nil {
+				tp = fd.irField.Type()
+			}
+			rep := state.representative(ref)
+			fmap := state.PartitionFieldMap(rep)
+			next, ok := fmap[fd]
+			if !ok {
+				next = fieldNamed(fmap, name)
+			}
+			if next == nil {
+				next = state.Insert(MakeSyntheticField(rep, name))
+			}
+			fmap[fd] = next
+			ref = next
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// Return the field with a given name of a struct type, or of the struct type
+// a pointer points to. If there is no such field, e.g. because the type is
+// not known, the field is only identified by its name.
+func fieldByName(tp types.Type, name string) Field {
+	if pt, ok := tp.(*types.Pointer); ok {
+		tp = pt.Elem()
+	}
+	if tp != nil {
+		if st, ok := tp.Underlying().(*types.Struct); ok {
+			for i := 0; i < st.NumFields(); i++ {
+				if f := st.Field(i); f.Name() == name {
+					return Field{Name: name, irField: f}
+				}
+			}
+		}
+	}
+	return Field{Name: name}
+}
+
+// Return the reference of the field with a given name in a field map, or nil.
+// The fields of the summaries are identified by name only. If several fields
+// have the name, that of the field of the IR is returned.
+func fieldNamed(fmap FieldMap, name string) Reference {
+	var named Reference
+	for fd, ref := range fmap {
+		if fd.Name != name {
+			continue
+		}
+		if fd.irField != nil {
+			return ref
+		}
+		named = ref
+	}
+	return named
+}
+
+// Return the global of a program with a name given by globalName, or nil.
+func lookupGlobal(prog *ssa.Program, name string) *ssa.Global {
+	i := strings.LastIndex(name, ".")
+	if i < 0 {
+		return nil
+	}
+	pkg := prog.ImportedPackage(name[:i])
+	if pkg == nil {
+		return nil
+	}
+	g, _ := pkg.Members[name[i+1:]].(*ssa.Global)
+	return g
+}
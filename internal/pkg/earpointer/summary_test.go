@@ -0,0 +1,60 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package earpointer
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+)
+
+func TestSummaryEncodingIsDeterministic(t *testing.T) {
+	var params []sinkParam
+	for i := 0; i < 16; i++ {
+		params = append(params, sinkParam{Pos: i, Labels: []string{"credentials", "pii"}})
+	}
+	s := &heapSummary{SinkParams: params}
+	var want []byte
+	for i := 0; i < 8; i++ {
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(s); err != nil {
+			t.Fatal(err)
+		}
+		if i > 0 && !bytes.Equal(buf.Bytes(), want) {
+			t.Fatalf("encoding %d of the summary differs from the first one", i)
+		}
+		want = buf.Bytes()
+	}
+}
+
+func TestSummarySinkLabels(t *testing.T) {
+	s := &heapSummary{SinkParams: []sinkParam{
+		{Pos: 0, Labels: []string{"credentials"}},
+		{Pos: 2, Labels: []string{"credentials", "pii"}},
+	}}
+	for _, tc := range []struct {
+		pos  int
+		want int
+	}{
+		{0, 1},
+		{1, 0},
+		{2, 2},
+		{3, 0},
+	} {
+		if got := len(s.sinkLabels(tc.pos)); got != tc.want {
+			t.Errorf("got %d labels for param %d, want %d", got, tc.pos, tc.want)
+		}
+	}
+}
@@ -16,6 +16,7 @@ package earpointer
 
 import (
 	"go/types"
+	"sort"
 
 	"golang.org/x/tools/go/callgraph"
 
@@ -34,6 +35,8 @@ type heapTraversal struct {
 	// The visited references during the traversal.
 	visited      ReferenceSet
 	isTaintField func(named *types.Named, index int) bool
+	// The calls to sanitizers within the reachable functions.
+	sanitizations []*sanitization
 }
 
 func (ht *heapTraversal) isWithinCallees(ref Reference) bool {
@@ -127,35 +130,97 @@ func (ht *heapTraversal) fieldRefs(ref Reference, result ReferenceSet) {
 	}
 }
 
-// Return any of the sources if it can reach the taint; otherwise return nil.
+// Return the sources that can reach the taint with a given label through
+// one of the sink's sensitive arguments "args" without being sanitized,
+// in the order of "sources".
 // Argument "srcRefs" maps a source to its alias references.
-func (ht *heapTraversal) canReach(sink ssa.Instruction, sources []*source.Source, srcRefs map[*source.Source]ReferenceSet) *source.Source {
+func (ht *heapTraversal) canReach(sink ssa.Instruction, args []ssa.Value, label string,
+	sources []*source.Source, srcRefs map[*source.Source]ReferenceSet) []*source.Source {
 	// Obtain the alias references of a sink.
 	// All sub-fields of a sink object are considered.
 	// For example, for heap "{t0}: [0->t1(taint), 1->t2]", return true for
 	// sink call "sinkf(t0)" since t0 contains a taint field t1.
 	sinkedRefs := make(map[Reference]bool)
-	for _, op := range sink.Operands(nil) {
+	for _, v := range args {
 		// Use a separate heapTraversal to search for the sink references.
 		sinkHT := &heapTraversal{heap: ht.heap, reachableFns: ht.reachableFns, visited: make(ReferenceSet)}
-		v := *op
 		if isLocal(v) || isGlobal(v) {
 			ref := MakeLocalWithEmptyContext(v)
 			sinkHT.fieldRefs(ref, sinkedRefs)
 		}
 	}
-	// Match each sink with any possible source.
-	for sink := range sinkedRefs {
-		members := ht.heap.PartitionMembers(sink)
-		for _, m := range members {
-			for _, src := range sources {
-				if srcRefs[src][m] {
-					return src
+	// Match each sink with every possible source.
+	var reached []*source.Source
+	for _, src := range sources {
+	search:
+		for ref := range sinkedRefs {
+			for _, m := range ht.heap.PartitionMembers(ref) {
+				if srcRefs[src][m] && !ht.isSanitized(m, src, sink, label) {
+					reached = append(reached, src)
+					break search
 				}
 			}
 		}
 	}
-	return nil
+	return reached
+}
+
+// Return the sources that can reach the taint through one of the arguments
+// "args" of a sink, in the order of "sources", along with the labels with which
+// each source reaches the sink. Argument "isSinkArg" determines whether the
+// argument at a given position is sensitive for a given label.
+func (ht *heapTraversal) reachingSources(sink ssa.Instruction, args []ssa.Value, isSinkArg func(pos int, label string) bool,
+	sources []*source.Source, srcRefs map[*source.Source]ReferenceSet) ([]*source.Source, map[*source.Source][]string) {
+
+	labels := make(map[*source.Source][]string)
+	for _, l := range sourceLabels(sources) {
+		var sensitive []ssa.Value
+		for i, a := range args {
+			if isSinkArg(i, l) {
+				sensitive = append(sensitive, a)
+			}
+		}
+		var labeled []*source.Source
+		for _, src := range sources {
+			if hasLabel(src, l) {
+				labeled = append(labeled, src)
+			}
+		}
+		for _, src := range ht.canReach(sink, sensitive, l, labeled, srcRefs) {
+			labels[src] = append(labels[src], l)
+		}
+	}
+	var reached []*source.Source
+	for _, src := range sources {
+		if len(labels[src]) > 0 {
+			reached = append(reached, src)
+		}
+	}
+	return reached, labels
+}
+
+// Return the labels of the sources, in order of appearance.
+func sourceLabels(sources []*source.Source) []string {
+	var labels []string
+	seen := make(map[string]bool)
+	for _, src := range sources {
+		for _, l := range src.Labels {
+			if !seen[l] {
+				seen[l] = true
+				labels = append(labels, l)
+			}
+		}
+	}
+	return labels
+}
+
+func hasLabel(src *source.Source, label string) bool {
+	for _, l := range src.Labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
 }
 
 // For a function, transitively get the functions reachable from this function
@@ -188,32 +253,49 @@ func boundedReachableFunctions(fn *ssa.Function, cg *callgraph.Graph, depth uint
 // Argument "heap" is an immutable EAR heap containing alias information;
 // "reachable" is used to bound the searching of source references in the heap.
 func srcAliasRefs(src *source.Source, isTaintField func(named *types.Named, index int) bool,
-	heap *Partitions, reachable map[*ssa.Function]bool) ReferenceSet {
+	heap *Partitions, reachable map[*ssa.Function]bool, conf *config.Config) ReferenceSet {
 
 	val, ok := src.Node.(ssa.Value)
 	if !ok {
 		return nil
 	}
-	rep := heap.Representative(MakeLocalWithEmptyContext(val))
+	ref := MakeReference(&emptyContext, val)
+	if !heap.Has(ref) {
+		return nil
+	}
+	rep := heap.Representative(ref)
 	refs := make(ReferenceSet)
 	ht := &heapTraversal{heap: heap, reachableFns: reachable, visited: make(ReferenceSet), isTaintField: isTaintField}
-	ht.srcRefs(rep, val.Type(), refs)
+	if _, ok := val.(*ssa.Global); ok || source.IsSourceFunctionResult(val, conf) {
+		// A value returned by a source function is tainted as a whole,
+		// regardless of its type, and so is a global holding a source
+		// of another package.
+		ht.fieldRefs(rep, refs)
+	} else {
+		ht.srcRefs(rep, val.Type(), refs)
+	}
 	return refs
 }
 
 type SourceSinkTrace struct {
-	Src       *source.Source
-	Sink      ssa.Instruction
-	Callstack []ssa.Call
+	Src  *source.Source
+	Sink ssa.Instruction
+	// The call sites through which the taint flows from the source's function
+	// to the sink's function, either into a callee or back to a caller.
+	Callstack []ssa.CallInstruction
+	// The labels with which the taint reaches the sink.
+	Labels []string
 }
 
 // Look for <source, sink> pairs by examining the heap alias information.
+// Every source reaching a sink is traced, and the traces are ordered by
+// the position of the sink, then by the position of the source.
 func SourcesToSinks(funcSources source.ResultType, isTaintField func(named *types.Named, index int) bool,
-	heap *Partitions, conf *config.Config) map[ssa.Instruction]*SourceSinkTrace {
+	heap *Partitions, conf *config.Config) []*SourceSinkTrace {
 
 	// A map from a callsite to its possible callees.
-	calleeMap := mapCallees(heap.cg)
-	traces := make(map[ssa.Instruction]*SourceSinkTrace)
+	calleeMap := heap.callees
+	tc := &traceCollector{cg: heap.cg, seen: make(map[sourceSink]bool)}
 	for fn, sources := range funcSources {
 		// Transitively get the set of functions reachable from "fn".
 		// This set is used to narrow down the set of references needed to be
@@ -231,42 +313,154 @@ func SourcesToSinks(funcSources source.ResultType, isTaintField func(named *type
 		// Start from the set of taint sources.
 		srcRefs := make(map[*source.Source]ReferenceSet)
 		for _, s := range sources {
-			srcRefs[s] = srcAliasRefs(s, isTaintField, heap, reachable)
+			srcRefs[s] = srcAliasRefs(s, isTaintField, heap, reachable, conf)
 		}
 		// Traverse all the reachable functions (not just the ones with sink sources)
 		// in search for connected sinks.
-		ht := &heapTraversal{heap: heap, reachableFns: reachable, visited: make(ReferenceSet)}
-		for member := range reachable {
-			for _, b := range member.Blocks {
-				for _, instr := range b.Instrs {
-					switch v := instr.(type) {
-					case *ssa.Call:
-						callees := calleeMap[&v.Call]
-						sink := instr
-						for _, callee := range callees {
-							if conf.IsSink(utils.DecomposeFunction(callee)) {
-								if src := ht.canReach(sink, sources, srcRefs); src != nil {
-									// If a previous source has been found, be in favor of the source within the same
-									// function. This can be extended to be in favor of the source closest to the sink.
-									if _, ok := traces[instr]; !ok || src.Node.Parent() == sink.Parent() {
-										traces[sink] = &SourceSinkTrace{Src: src, Sink: sink}
-									}
-								}
-							}
+		ht := &heapTraversal{heap: heap, reachableFns: reachable, visited: make(ReferenceSet),
+			sanitizations: collectSanitizations(heap, reachable, calleeMap, conf)}
+		tc.reachable = reachable
+		ht.traceSinks(sources, srcRefs, calleeMap, conf, tc)
+	}
+	sort.Slice(tc.traces, func(i, j int) bool {
+		ti, tj := tc.traces[i], tc.traces[j]
+		if ti.Sink.Pos() != tj.Sink.Pos() {
+			return ti.Sink.Pos() < tj.Sink.Pos()
+		}
+		return ti.Src.Pos() < tj.Src.Pos()
+	})
+	return tc.traces
+}
+
+// Record the traces of the sources reaching the sinks within the reachable functions.
+// Besides the configured sinks, the calls to functions of other packages whose
+// summaries have parameters reaching a sink are sinks.
+func (ht *heapTraversal) traceSinks(sources []*source.Source, srcRefs map[*source.Source]ReferenceSet,
+	calleeMap map[*ssa.CallCommon][]*ssa.Function, conf *config.Config, tc *traceCollector) {
+
+	for member := range ht.reachableFns {
+		for _, b := range member.Blocks {
+			for _, instr := range b.Instrs {
+				switch v := instr.(type) {
+				case *ssa.Panic:
+					if !conf.AllowPanicOnTaintedValues {
+						ht.tracePanic(instr, v.X, sources, srcRefs, tc)
+					}
+				// Calls made through go and defer statements are sinks as well.
+				case ssa.CallInstruction:
+					// panic can only be called through go and defer statements,
+					// since direct calls are represented by Panic instructions.
+					if b, ok := v.Common().Value.(*ssa.Builtin); ok && b.Name() == "panic" {
+						if !conf.AllowPanicOnTaintedValues {
+							ht.tracePanic(instr, v.Common().Args[0], sources, srcRefs, tc)
 						}
-					case *ssa.Panic:
-						if conf.AllowPanicOnTaintedValues {
+						continue
+					}
+					callees := calleeMap[v.Common()]
+					sink := instr
+					for _, callee := range callees {
+						var isSinkArg func(pos int, label string) bool
+						if conf.IsSink(utils.DecomposeFunction(callee)) {
+							path, recv, name := utils.DecomposeFunction(callee)
+							isSinkArg = func(pos int, label string) bool {
+								return conf.IsSinkArg(path, recv, name, pos, label)
+							}
+						} else if summ := ht.heap.summaries[callee.Object()]; summ != nil && len(summ.SinkParams) > 0 && len(callee.Blocks) == 0 {
+							isSinkArg = func(pos int, label string) bool {
+								return containsLabel(summ.sinkLabels(pos), label)
+							}
+						} else {
 							continue
 						}
-						sink := instr
-						if src := ht.canReach(sink, sources, srcRefs); src != nil {
-							traces[sink] = &SourceSinkTrace{Src: src, Sink: sink}
-						}
-
+						reached, labels := ht.reachingSources(sink, utils.CallArgs(v.Common()), isSinkArg, sources, srcRefs)
+						tc.add(sink, reached, labels)
 					}
 				}
 			}
 		}
 	}
-	return traces
+}
+
+type sourceSink struct {
+	src  *source.Source
+	sink ssa.Instruction
+}
+
+// Collect the traces of the sources reaching sinks, once per <source, sink> pair.
+type traceCollector struct {
+	cg *callgraph.Graph
+	// The functions reachable from the sources being examined.
+	reachable map[*ssa.Function]bool
+	seen      map[sourceSink]bool
+	traces    []*SourceSinkTrace
+}
+
+func (tc *traceCollector) add(sink ssa.Instruction, reached []*source.Source, labels map[*source.Source][]string) {
+	for _, src := range reached {
+		if tc.seen[sourceSink{src, sink}] {
+			continue
+		}
+		tc.seen[sourceSink{src, sink}] = true
+		tc.traces = append(tc.traces, &SourceSinkTrace{Src: src, Sink: sink, Labels: labels[src],
+			Callstack: callChain(tc.cg, src.Node.Parent(), sink.Parent(), tc.reachable)})
+	}
+}
+
+// tracePanic records the traces of the sources reaching a panic with value x.
+func (ht *heapTraversal) tracePanic(sink ssa.Instruction, x ssa.Value, sources []*source.Source, srcRefs map[*source.Source]ReferenceSet, tc *traceCollector) {
+	// panic is a sink for all labels.
+	isSinkArg := func(int, string) bool { return true }
+	reached, labels := ht.reachingSources(sink, []ssa.Value{x}, isSinkArg, sources, srcRefs)
+	tc.add(sink, reached, labels)
+}
+
+// Obtain the call sites along a shortest chain of calls and returns leading
+// from function "from" to function "to" within the reachable functions.
+// For example,
+//   func f(){ g(); h() }
+// for from = g and to = h, the chain is [g(), h()]: g returns to f,
+// which calls h. Return nil if the functions are the same or not connected,
+// or if "from" is nil, e.g. for a global.
+func callChain(cg *callgraph.Graph, from, to *ssa.Function, reachable map[*ssa.Function]bool) []ssa.CallInstruction {
+	if from == nil || from == to {
+		return nil
+	}
+	type link struct {
+		site ssa.CallInstruction
+		prev *ssa.Function
+	}
+	links := map[*ssa.Function]link{from: {}}
+	queue := []*ssa.Function{from}
+	for len(queue) > 0 {
+		fn := queue[0]
+		queue = queue[1:]
+		node := cg.Nodes[fn]
+		if node == nil {
+			continue
+		}
+		visit := func(next *ssa.Function, site ssa.CallInstruction) {
+			if _, ok := links[next]; ok || !reachable[next] {
+				return
+			}
+			links[next] = link{site: site, prev: fn}
+			queue = append(queue, next)
+		}
+		for _, out := range node.Out {
+			visit(out.Callee.Func, out.Site)
+		}
+		for _, in := range node.In {
+			visit(in.Caller.Func, in.Site)
+		}
+		if _, ok := links[to]; ok {
+			break
+		}
+	}
+	if _, ok := links[to]; !ok {
+		return nil
+	}
+	var chain []ssa.CallInstruction
+	for fn := to; fn != from; fn = links[fn].prev {
+		chain = append([]ssa.CallInstruction{links[fn].site}, chain...)
+	}
+	return chain
 }
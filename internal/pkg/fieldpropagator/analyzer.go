@@ -20,9 +20,12 @@ import (
 	"go/types"
 	"reflect"
 
+	"github.com/google/go-flow-levee/internal/pkg/callees"
 	"github.com/google/go-flow-levee/internal/pkg/config"
 	"github.com/google/go-flow-levee/internal/pkg/fieldtags"
 	"github.com/google/go-flow-levee/internal/pkg/propagation"
+	infer "github.com/google/go-flow-levee/internal/pkg/sourceinfer"
+	"github.com/google/go-flow-levee/internal/pkg/sourcetype"
 	"github.com/google/go-flow-levee/internal/pkg/utils"
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/buildssa"
@@ -46,23 +49,32 @@ func (i isFieldPropagator) String() string {
 	return "field propagator identified"
 }
 
-var Analyzer = &analysis.Analyzer{
-	Name: "fieldpropagator",
-	Doc: `This analyzer identifies field propagators.
+// Analyzer reads its configuration from the file selected by the -config flag.
+var Analyzer = NewAnalyzer(nil, fieldtags.Analyzer, infer.Analyzer)
+
+// NewAnalyzer returns an analyzer bound to conf, which requires the taggedFields and
+// inferredSources analyzers bound to the same configuration. If conf is nil,
+// the analyzer reads its configuration as Analyzer does.
+func NewAnalyzer(conf *config.Config, taggedFields, inferredSources *analysis.Analyzer) *analysis.Analyzer {
+	return &analysis.Analyzer{
+		Name: "fieldpropagator",
+		Doc: `This analyzer identifies field propagators.
 
 A field propagator is a function that returns a value that is tainted by a source field.`,
-	Flags:      config.FlagSet,
-	Run:        run,
-	Requires:   []*analysis.Analyzer{buildssa.Analyzer, fieldtags.Analyzer},
-	ResultType: reflect.TypeOf(new(ResultType)).Elem(),
-	FactTypes:  []analysis.Fact{new(isFieldPropagator)},
+		Flags: config.NewFlagSet(conf),
+		Run: func(pass *analysis.Pass) (interface{}, error) {
+			return run(pass, conf, pass.ResultOf[taggedFields].(fieldtags.ResultType), pass.ResultOf[inferredSources].(infer.ResultType))
+		},
+		Requires:   []*analysis.Analyzer{buildssa.Analyzer, taggedFields, inferredSources},
+		ResultType: reflect.TypeOf(new(ResultType)).Elem(),
+		FactTypes:  []analysis.Fact{new(isFieldPropagator)},
+	}
 }
 
-func run(pass *analysis.Pass) (interface{}, error) {
-	taggedFields := pass.ResultOf[fieldtags.Analyzer].(fieldtags.ResultType)
+func run(pass *analysis.Pass, conf *config.Config, taggedFields fieldtags.ResultType, inferred infer.ResultType) (interface{}, error) {
 	ssaInput := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
 
-	conf, err := config.ReadConfig()
+	conf, err := config.Load(conf, pass.Pkg.Path())
 	if err != nil {
 		return nil, err
 	}
@@ -70,11 +82,11 @@ func run(pass *analysis.Pass) (interface{}, error) {
 	ssaProg := ssaInput.Pkg.Prog
 	for _, mem := range ssaInput.Pkg.Members {
 		ssaType, ok := mem.(*ssa.Type)
-		if !ok || !conf.IsSourceType(utils.DecomposeType(ssaType.Type())) {
+		if !ok || !(conf.IsSourceType(utils.DecomposeType(ssaType.Type())) || inferred.IsSourceType(ssaType.Type())) {
 			continue
 		}
 		for _, meth := range methods(ssaProg, ssaType.Type()) {
-			analyzeBlocks(pass, conf, taggedFields, meth)
+			analyzeBlocks(pass, conf, taggedFields, inferred, meth)
 		}
 	}
 
@@ -105,7 +117,7 @@ func methodValues(ssaProg *ssa.Program, t types.Type) []*ssa.Function {
 	return methodValues
 }
 
-func analyzeBlocks(pass *analysis.Pass, conf *config.Config, tf fieldtags.ResultType, meth *ssa.Function) {
+func analyzeBlocks(pass *analysis.Pass, conf *config.Config, tf fieldtags.ResultType, inferred infer.ResultType, meth *ssa.Function) {
 	var propagations []propagation.Propagation
 
 	for _, b := range meth.Blocks {
@@ -124,8 +136,8 @@ func analyzeBlocks(pass *analysis.Pass, conf *config.Config, tf fieldtags.Result
 			default:
 				continue
 			}
-			if conf.IsSourceField(utils.DecomposeField(txType, field)) || tf.IsSourceField(txType, field) {
-				propagations = append(propagations, propagation.Taint(instr.(ssa.Node), conf, tf))
+			if conf.IsSourceField(utils.DecomposeField(txType, field)) || tf.IsSourceField(txType, field) || holdsSourceType(conf, inferred, txType, field) {
+				propagations = append(propagations, propagation.Taint(instr.(ssa.Node), sourcetype.Labels(conf, inferred, txType), conf, tf, nil, callees.ResultType{}))
 			}
 		}
 	}
@@ -144,3 +156,13 @@ func analyzeBlocks(pass *analysis.Pass, conf *config.Config, tf fieldtags.Result
 		}
 	}
 }
+
+// holdsSourceType determines whether a field holds a value of a source type,
+// such as the fields that cause a struct type to be inferred to be a source type.
+func holdsSourceType(conf *config.Config, inferred infer.ResultType, t types.Type, field int) bool {
+	if !conf.InferSources {
+		return false
+	}
+	ft := utils.Dereference(utils.Dereference(t).Underlying().(*types.Struct).Field(field).Type())
+	return conf.IsSourceType(utils.DecomposeType(ft)) || inferred.IsSourceType(ft)
+}
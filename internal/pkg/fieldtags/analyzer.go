@@ -32,15 +32,24 @@ import (
 // It can be used to determine whether a field is a tagged Source field.
 type ResultType map[types.Object]bool
 
-var Analyzer = &analysis.Analyzer{
-	Name: "fieldtags",
-	Doc:  "This analyzer identifies Source fields based on their tags.",
-	Run:  run,
-	Requires: []*analysis.Analyzer{
-		inspect.Analyzer,
-	},
-	ResultType: reflect.TypeOf(new(ResultType)).Elem(),
-	FactTypes:  []analysis.Fact{new(isTaggedField)},
+// Analyzer reads its configuration from the file selected by the -config flag.
+var Analyzer = NewAnalyzer(nil)
+
+// NewAnalyzer returns an analyzer bound to conf. If conf is nil,
+// the analyzer reads its configuration as Analyzer does.
+func NewAnalyzer(conf *config.Config) *analysis.Analyzer {
+	return &analysis.Analyzer{
+		Name: "fieldtags",
+		Doc:  "This analyzer identifies Source fields based on their tags.",
+		Run: func(pass *analysis.Pass) (interface{}, error) {
+			return run(pass, conf)
+		},
+		Requires: []*analysis.Analyzer{
+			inspect.Analyzer,
+		},
+		ResultType: reflect.TypeOf(new(ResultType)).Elem(),
+		FactTypes:  []analysis.Fact{new(isTaggedField)},
+	}
 }
 
 type isTaggedField struct{}
@@ -51,8 +60,8 @@ func (i isTaggedField) String() string {
 	return "tagged field"
 }
 
-func run(pass *analysis.Pass) (interface{}, error) {
-	conf, err := config.ReadConfig()
+func run(pass *analysis.Pass, conf *config.Config) (interface{}, error) {
+	conf, err := config.Load(conf, pass.Pkg.Path())
 	if err != nil {
 		return nil, err
 	}
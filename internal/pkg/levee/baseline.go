@@ -0,0 +1,102 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package levee
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// baselineHeader is the first line of a baseline file.
+const baselineHeader = "# levee baseline: findings with these fingerprints are not reported."
+
+// baselineFile returns the path of the baseline file of the package of a pass.
+func baselineFile(dir string, pass *analysis.Pass) string {
+	return packageFile(dir, pass) + ".baseline"
+}
+
+// readBaseline returns the fingerprints held by the baseline file of a package.
+// Each line of the file starts with a fingerprint, which may be followed by
+// a description of the finding. Empty lines and lines starting with '#' are ignored.
+// A package without a baseline file has no known findings.
+func readBaseline(dir string, pass *analysis.Pass) (map[string]bool, error) {
+	f, err := os.Open(baselineFile(dir, pass))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading baseline: %v", err)
+	}
+	defer f.Close()
+	fingerprints := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fingerprints[strings.Fields(line)[0]] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading baseline: %v", err)
+	}
+	return fingerprints, nil
+}
+
+// A baselineEntry is a line of a baseline file.
+type baselineEntry struct {
+	fingerprint string
+	// A description of the finding, for readers of the baseline file.
+	description string
+}
+
+// writeBaseline writes the entries of a package to its baseline file.
+// The entries are sorted, so that the file does not depend on the order
+// of the findings. As for SARIF output, the file of a package without
+// entries is removed.
+func writeBaseline(dir string, pass *analysis.Pass, entries []baselineEntry) error {
+	file := baselineFile(dir, pass)
+	if len(entries) == 0 {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error writing baseline: %v", err)
+		}
+		return nil
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		ei, ej := entries[i], entries[j]
+		if ei.description != ej.description {
+			return ei.description < ej.description
+		}
+		return ei.fingerprint < ej.fingerprint
+	})
+	var b strings.Builder
+	fmt.Fprintln(&b, baselineHeader)
+	for _, e := range entries {
+		fmt.Fprintf(&b, "%s %s\n", e.fingerprint, e.description)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("error writing baseline: %v", err)
+	}
+	if err := ioutil.WriteFile(file, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("error writing baseline: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package levee
+
+import (
+	"encoding/gob"
+	"fmt"
+	"go/types"
+	"reflect"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// A driver only allows a fact type to be recorded by a single analyzer,
+// but the analyzers bound to different configurations by NewAnalyzers
+// record facts of the same types. Hence they are not run by the driver.
+// Instead, a runner analyzer runs the analyzers bound to each of the
+// configurations, and records their facts in boundFacts, under a key
+// identifying the configuration and the analyzer.
+
+// A boundGraph holds the analyzers bound to a configuration.
+type boundGraph struct {
+	// The position of the configuration among those given to NewAnalyzers.
+	id int
+	// The analyzers, ordered so that each analyzer follows those it requires.
+	analyzers []*analysis.Analyzer
+	// The analyzers that the analyzers require, and that are not bound to the configuration.
+	requires []*analysis.Analyzer
+}
+
+// boundResults holds the results of the analyzers bound to each configuration,
+// and the errors of the graphs in which an analyzer failed.
+type boundResults struct {
+	results map[*analysis.Analyzer]interface{}
+	errs    map[*boundGraph]error
+}
+
+// newGraph returns the graph of the analyzers bound to a configuration,
+// which must include every analyzer that they require that is bound
+// to the same configuration.
+func newGraph(id int, analyzers ...*analysis.Analyzer) *boundGraph {
+	g := &boundGraph{id: id}
+	inGraph := make(map[*analysis.Analyzer]bool)
+	for _, a := range analyzers {
+		inGraph[a] = true
+	}
+	visited := make(map[*analysis.Analyzer]bool)
+	var visit func(a *analysis.Analyzer)
+	visit = func(a *analysis.Analyzer) {
+		if visited[a] {
+			return
+		}
+		visited[a] = true
+		for _, r := range a.Requires {
+			if inGraph[r] {
+				visit(r)
+			} else {
+				g.requires = appendAnalyzer(g.requires, r)
+			}
+		}
+		for _, f := range a.FactTypes {
+			// The facts are encoded as interface values.
+			gob.Register(f)
+		}
+		g.analyzers = append(g.analyzers, a)
+	}
+	for _, a := range analyzers {
+		visit(a)
+	}
+	return g
+}
+
+// newRunner returns an analyzer running the analyzers of the graphs.
+// It requires the analyzers that they require, and that are not bound to a configuration.
+func newRunner(graphs []*boundGraph) *analysis.Analyzer {
+	var requires []*analysis.Analyzer
+	for _, g := range graphs {
+		for _, r := range g.requires {
+			requires = appendAnalyzer(requires, r)
+		}
+	}
+	return &analysis.Analyzer{
+		Name:       "leveebound",
+		Doc:        "runs the analyzers required by the levee analyzers bound to a configuration",
+		Run:        func(pass *analysis.Pass) (interface{}, error) { return runGraphs(pass, graphs), nil },
+		Requires:   requires,
+		ResultType: reflect.TypeOf(new(boundResults)),
+		FactTypes:  []analysis.Fact{new(boundFacts)},
+	}
+}
+
+// appendAnalyzer appends an analyzer to a list, unless the list already holds it.
+func appendAnalyzer(analyzers []*analysis.Analyzer, a *analysis.Analyzer) []*analysis.Analyzer {
+	for _, other := range analyzers {
+		if other == a {
+			return analyzers
+		}
+	}
+	return append(analyzers, a)
+}
+
+// runGraphs runs the analyzers of each graph. If an analyzer fails,
+// the remaining analyzers of its graph are not run.
+func runGraphs(pass *analysis.Pass, graphs []*boundGraph) *boundResults {
+	r := &boundResults{
+		results: make(map[*analysis.Analyzer]interface{}),
+		errs:    make(map[*boundGraph]error),
+	}
+	for _, g := range graphs {
+		for _, a := range g.analyzers {
+			res, err := a.Run(g.pass(pass, a, r))
+			if err != nil {
+				r.errs[g] = fmt.Errorf("%s: %v", a.Name, err)
+				break
+			}
+			r.results[a] = res
+		}
+	}
+	return r
+}
+
+// pass returns a view of a pass of the runner for an analyzer of the graph.
+// The results of the analyzers that it requires include those of the graph's
+// analyzers, and its facts are recorded under the key of the analyzer.
+func (g *boundGraph) pass(pass *analysis.Pass, a *analysis.Analyzer, r *boundResults) *analysis.Pass {
+	view := r.view(pass)
+	view.Analyzer = a
+	key := fmt.Sprintf("%d.%s", g.id, a.Name)
+	view.ImportObjectFact = func(obj types.Object, fact analysis.Fact) bool {
+		var facts boundFacts
+		return pass.ImportObjectFact(obj, &facts) && facts.get(key, fact)
+	}
+	view.ExportObjectFact = func(obj types.Object, fact analysis.Fact) {
+		var facts boundFacts
+		pass.ImportObjectFact(obj, &facts)
+		pass.ExportObjectFact(obj, facts.with(key, fact))
+	}
+	view.AllObjectFacts = func() []analysis.ObjectFact {
+		var all []analysis.ObjectFact
+		for _, f := range pass.AllObjectFacts() {
+			if fact := f.Fact.(*boundFacts).lookup(key); fact != nil {
+				all = append(all, analysis.ObjectFact{Object: f.Object, Fact: fact})
+			}
+		}
+		return all
+	}
+	view.ImportPackageFact = func(pkg *types.Package, fact analysis.Fact) bool {
+		var facts boundFacts
+		return pass.ImportPackageFact(pkg, &facts) && facts.get(key, fact)
+	}
+	view.ExportPackageFact = func(fact analysis.Fact) {
+		var facts boundFacts
+		pass.ImportPackageFact(pass.Pkg, &facts)
+		pass.ExportPackageFact(facts.with(key, fact))
+	}
+	view.AllPackageFacts = func() []analysis.PackageFact {
+		var all []analysis.PackageFact
+		for _, f := range pass.AllPackageFacts() {
+			if fact := f.Fact.(*boundFacts).lookup(key); fact != nil {
+				all = append(all, analysis.PackageFact{Package: f.Package, Fact: fact})
+			}
+		}
+		return all
+	}
+	return view
+}
+
+// view returns a view of a pass in which the results of the analyzers
+// it requires include those of the analyzers bound to configurations.
+func (r *boundResults) view(pass *analysis.Pass) *analysis.Pass {
+	view := *pass
+	view.ResultOf = make(map[*analysis.Analyzer]interface{}, len(pass.ResultOf)+len(r.results))
+	for req, res := range pass.ResultOf {
+		view.ResultOf[req] = res
+	}
+	for req, res := range r.results {
+		view.ResultOf[req] = res
+	}
+	return &view
+}
+
+// boundFacts holds the facts of an object or a package recorded by
+// the analyzers bound to configurations.
+type boundFacts struct {
+	// Ordered by key, so that the encoding of the facts is deterministic.
+	Facts []boundFact
+}
+
+// A boundFact is a fact recorded by the analyzer with the given key.
+type boundFact struct {
+	Key  string
+	Fact analysis.Fact
+}
+
+func (*boundFacts) AFact() {}
+
+func (bf *boundFacts) String() string {
+	return fmt.Sprint(bf.Facts)
+}
+
+// lookup returns the fact recorded under a key, if any.
+func (bf *boundFacts) lookup(key string) analysis.Fact {
+	i := sort.Search(len(bf.Facts), func(i int) bool { return bf.Facts[i].Key >= key })
+	if i < len(bf.Facts) && bf.Facts[i].Key == key {
+		return bf.Facts[i].Fact
+	}
+	return nil
+}
+
+// get copies the fact recorded under a key into fact, which must be a pointer
+// of the same type, and reports whether there is such a fact.
+func (bf *boundFacts) get(key string, fact analysis.Fact) bool {
+	f := bf.lookup(key)
+	if f == nil {
+		return false
+	}
+	reflect.ValueOf(fact).Elem().Set(reflect.ValueOf(f).Elem())
+	return true
+}
+
+// with returns a copy of the facts in which fact is recorded under a key.
+func (bf *boundFacts) with(key string, fact analysis.Fact) *boundFacts {
+	facts := make([]boundFact, 0, len(bf.Facts)+1)
+	for _, f := range bf.Facts {
+		if f.Key != key {
+			facts = append(facts, f)
+		}
+	}
+	facts = append(facts, boundFact{Key: key, Fact: fact})
+	sort.Slice(facts, func(i, j int) bool { return facts[i].Key < facts[j].Key })
+	return &boundFacts{Facts: facts}
+}
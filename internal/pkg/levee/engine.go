@@ -0,0 +1,146 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package levee
+
+import (
+	"fmt"
+	"go/token"
+
+	"github.com/google/go-flow-levee/internal/pkg/callees"
+	"github.com/google/go-flow-levee/internal/pkg/config"
+	"github.com/google/go-flow-levee/internal/pkg/earpointer"
+	"github.com/google/go-flow-levee/internal/pkg/fieldtags"
+	"github.com/google/go-flow-levee/internal/pkg/paramflow"
+	"github.com/google/go-flow-levee/internal/pkg/propagation"
+	"github.com/google/go-flow-levee/internal/pkg/source"
+	infer "github.com/google/go-flow-levee/internal/pkg/sourceinfer"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ssa"
+)
+
+// A TaintEngine finds the sources that reach the sinks of a package.
+type TaintEngine interface {
+	// Name returns the name by which the -engine flag selects the engine.
+	Name() config.Engine
+	// Findings returns the findings of a package, given the sources of each of
+	// its functions. Each source of a finding comes with its trace to the sink.
+	Findings(pass *analysis.Pass, conf *config.Config, funcSources source.ResultType) ([]finding, error)
+}
+
+// propagationEngine follows the propagation of taint from SSA values to their referrers.
+type propagationEngine struct {
+	req requirements
+}
+
+func (propagationEngine) Name() config.Engine {
+	return config.PropagationEngine
+}
+
+func (e propagationEngine) Findings(pass *analysis.Pass, conf *config.Config, funcSources source.ResultType) ([]finding, error) {
+	taggedFields := pass.ResultOf[e.req.fieldTags].(fieldtags.ResultType)
+	flows := pass.ResultOf[e.req.paramFlow].(paramflow.ResultType)
+	resolved := pass.ResultOf[e.req.callees].(callees.ResultType)
+
+	var findings []finding
+	for fn, sources := range funcSources {
+		propagations := make(map[*source.Source]propagation.Propagation, len(sources))
+		for _, s := range sources {
+			propagations[s] = propagation.Taint(s.Node, s.Labels, conf, taggedFields, flows, resolved)
+		}
+
+		for _, sink := range propagation.Sinks(fn, conf, flows, resolved) {
+			if f, ok := sourcesReachingSink(pass, sources, propagations, sink); ok {
+				findings = append(findings, f)
+			}
+		}
+	}
+	return findings, nil
+}
+
+// earEngine uses the EAR pointer analysis.
+type earEngine struct {
+	req requirements
+	// The directory to which the EAR heap of each package is written, if any.
+	heapDir string
+}
+
+func (earEngine) Name() config.Engine {
+	return config.EAREngine
+}
+
+func (e earEngine) Findings(pass *analysis.Pass, conf *config.Config, funcSources source.ResultType) ([]finding, error) {
+	heap := pass.ResultOf[e.req.earPointer].(*earpointer.Partitions)
+	if heap == nil {
+		return nil, fmt.Errorf("no valid EAR partitions")
+	}
+	taggedFields := pass.ResultOf[e.req.fieldTags].(fieldtags.ResultType)
+	inferredSources := pass.ResultOf[e.req.inferredSources].(infer.ResultType)
+	globals := pass.ResultOf[e.req.earGlobals].(earpointer.GlobalSources)
+	// The globals of other packages holding a source are sources as well.
+	funcSources = earpointer.AddGlobalSources(funcSources, globals, heap)
+	isTaintField := earpointer.TaintFields(conf, taggedFields, inferredSources)
+	// The traces are ordered by sink, so the traces of a sink are consecutive.
+	traces := earpointer.SourcesToSinks(funcSources, isTaintField, heap, conf)
+	if e.heapDir != "" {
+		if err := writeHeap(e.heapDir, pass, heap, traces); err != nil {
+			return nil, err
+		}
+	}
+	var findings []finding
+	for _, trace := range traces {
+		rs := reachingSource{src: trace.Src, labels: trace.Labels, trace: earTrace(trace)}
+		if n := len(findings); n > 0 && findings[n-1].sink == trace.Sink {
+			findings[n-1].sources = append(findings[n-1].sources, rs)
+			continue
+		}
+		findings = append(findings, finding{sink: trace.Sink, sources: []reachingSource{rs}})
+	}
+	return findings, nil
+}
+
+// A sourceAtSink identifies a source reaching a sink across engines.
+// Sources at the same position are indistinguishable in reports,
+// so a source is identified by its position.
+type sourceAtSink struct {
+	sink ssa.Instruction
+	src  token.Pos
+}
+
+// differences returns the findings that only one of two engines produces,
+// given the findings of each engine: for each finding of an engine, the sources
+// that the other engine does not find reaching the same sink, if there are any.
+func differences(engines []TaintEngine, findings [][]finding) []finding {
+	var diffs []finding
+	for i, engine := range engines {
+		others := make(map[sourceAtSink]bool)
+		for _, f := range findings[1-i] {
+			for _, rs := range f.sources {
+				others[sourceAtSink{f.sink, rs.src.Pos()}] = true
+			}
+		}
+		for _, f := range findings[i] {
+			diff := finding{sink: f.sink, args: f.args, onlyBy: engine.Name()}
+			for _, rs := range f.sources {
+				if !others[sourceAtSink{f.sink, rs.src.Pos()}] {
+					diff.sources = append(diff.sources, rs)
+				}
+			}
+			if len(diff.sources) > 0 {
+				diffs = append(diffs, diff)
+			}
+		}
+	}
+	return diffs
+}
@@ -0,0 +1,250 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package levee
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/go-flow-levee/internal/pkg/callees"
+	"github.com/google/go-flow-levee/internal/pkg/config"
+	"github.com/google/go-flow-levee/internal/pkg/source"
+	"github.com/google/go-flow-levee/internal/pkg/utils"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ssa"
+)
+
+// ruleID identifies the kind of findings reported by the analyzer.
+const ruleID = "source-reaches-sink"
+
+// A finding is a sink reached by one or more sources.
+type finding struct {
+	sink ssa.Instruction
+	// The sources reaching the sink. Once the finding is normalized,
+	// they are distinct and ordered by position.
+	sources []reachingSource
+	// The expressions passed to the sink through which the sources reach it,
+	// if they are known. Suggested fixes sanitize them.
+	args []ast.Expr
+	// In the differential mode, the only engine producing the finding.
+	onlyBy config.Engine
+}
+
+// A reachingSource is a source reaching the sink of a finding.
+type reachingSource struct {
+	src *source.Source
+	// The labels with which the source reaches the sink.
+	labels []string
+	// The path from the source to the sink.
+	trace trace
+	// If the sink is a call to a function whose parameters reach sinks,
+	// the position of the sink that the source reaches within that function.
+	inner token.Position
+}
+
+// normalize orders the sources and arguments of a finding by position.
+// Sources at the same position are indistinguishable in reports, so only
+// the first one is kept, with the labels of all of them.
+func (f *finding) normalize(pass *analysis.Pass) {
+	sort.Slice(f.args, func(i, j int) bool { return f.args[i].Pos() < f.args[j].Pos() })
+	sort.SliceStable(f.sources, func(i, j int) bool {
+		return positionLess(pass.Fset.Position(f.sources[i].src.Pos()), pass.Fset.Position(f.sources[j].src.Pos()))
+	})
+	var distinct []reachingSource
+	for _, rs := range f.sources {
+		if n := len(distinct); n > 0 && distinct[n-1].src.Pos() == rs.src.Pos() {
+			distinct[n-1].labels = union(distinct[n-1].labels, rs.labels)
+			if !distinct[n-1].inner.IsValid() {
+				distinct[n-1].inner = rs.inner
+			}
+			continue
+		}
+		distinct = append(distinct, rs)
+	}
+	f.sources = distinct
+}
+
+// labels returns the labels with which any source reaches the sink.
+func (f finding) labels() []string {
+	var labels []string
+	for _, rs := range f.sources {
+		labels = union(labels, rs.labels)
+	}
+	return labels
+}
+
+// innerSinks returns the distinct positions of the sinks reached by the sources
+// within the function called by the sink, in the order of the sources.
+func (f finding) innerSinks() []token.Position {
+	var inner []token.Position
+	seen := make(map[token.Position]bool)
+	for _, rs := range f.sources {
+		if rs.inner.IsValid() && !seen[rs.inner] {
+			seen[rs.inner] = true
+			inner = append(inner, rs.inner)
+		}
+	}
+	return inner
+}
+
+func union(labels, others []string) []string {
+	result := append([]string(nil), labels...)
+	for _, o := range others {
+		found := false
+		for _, l := range result {
+			if l == o {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, o)
+		}
+	}
+	return result
+}
+
+// sortFindings normalizes findings and sorts them by the position of their sink.
+func sortFindings(pass *analysis.Pass, findings []finding) {
+	for i := range findings {
+		findings[i].normalize(pass)
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return positionLess(pass.Fset.Position(findings[i].sink.Pos()), pass.Fset.Position(findings[j].sink.Pos()))
+	})
+}
+
+func positionLess(a, b token.Position) bool {
+	if a.Filename != b.Filename {
+		return a.Filename < b.Filename
+	}
+	if a.Line != b.Line {
+		return a.Line < b.Line
+	}
+	return a.Column < b.Column
+}
+
+// key identifies a finding without referring to positions,
+// so that it is not affected by unrelated changes to the code.
+// It is made of the path of the sink's file relative to its module,
+// the names of the functions holding the sink and the sources,
+// the name of the function called by the sink, the labels, the types of the sources,
+// and the patterns of the configuration entries matched by the finding.
+func (f finding) key(conf *config.Config, pass *analysis.Pass, resolved callees.ResultType) string {
+	sourcePatterns, sinkPatterns := f.configPatterns(conf, resolved)
+	parts := []string{
+		ruleID,
+		relativePath(pass, f.sink.Pos()),
+		f.sink.Parent().String(),
+		sinkName(f.sink),
+		strings.Join(namedLabels(f.labels()), ","),
+		strings.Join(sourcePatterns, ";"),
+		strings.Join(sinkPatterns, ";"),
+	}
+	for _, rs := range f.sources {
+		var srcType string
+		if v, ok := rs.src.Node.(ssa.Value); ok {
+			// Whether a source is held by a value or by a pointer to it depends on
+			// how the SSA form was built, rather than on the code.
+			srcType = utils.Dereference(v.Type()).String()
+		}
+		parts = append(parts, sourceScope(rs.src), srcType)
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// sourceScope returns the name of the function holding a source,
+// or the name of the global holding a source of another package.
+func sourceScope(src *source.Source) string {
+	if g, ok := src.Node.(*ssa.Global); ok {
+		return g.String()
+	}
+	return src.Node.Parent().String()
+}
+
+// configEntries returns the names of the configuration entries matched by
+// the sources and the sink of a finding, e.g. "Sources[0]" and "Sinks[2]".
+func (f finding) configEntries(conf *config.Config, resolved callees.ResultType) []string {
+	var entries []string
+	for _, rs := range f.sources {
+		entries = union(entries, rs.src.Entries)
+	}
+	return union(entries, sinkEntries(conf, resolved, f.sink))
+}
+
+// configPatterns returns the patterns of the configuration entries matched by
+// the sources and by the sink of a finding. Unlike the names of the entries,
+// they are sorted, so that they do not depend on the order of the entries
+// within the configuration.
+func (f finding) configPatterns(conf *config.Config, resolved callees.ResultType) (sources, sinks []string) {
+	for _, rs := range f.sources {
+		for _, e := range rs.src.Entries {
+			sources = union(sources, []string{conf.EntryPatterns(e)})
+		}
+	}
+	for _, e := range sinkEntries(conf, resolved, f.sink) {
+		sinks = union(sinks, []string{conf.EntryPatterns(e)})
+	}
+	sort.Strings(sources)
+	sort.Strings(sinks)
+	return sources, sinks
+}
+
+// relativePath returns the path of the file holding a position relative
+// to its module, i.e. the file's name within the path of its package.
+// Unlike the file's absolute path, it does not depend on where the module
+// is checked out.
+func relativePath(pass *analysis.Pass, pos token.Pos) string {
+	return path.Join(pass.Pkg.Path(), filepath.Base(pass.Fset.Position(pos).Filename))
+}
+
+// describe describes a finding in a baseline file, without referring to line numbers.
+func describe(pass *analysis.Pass, f finding) string {
+	return fmt.Sprintf("%s: %s calls %s", relativePath(pass, f.sink.Pos()), f.sink.Parent().Name(), sinkName(f.sink))
+}
+
+// sinkName names the function called by a sink.
+func sinkName(sink ssa.Instruction) string {
+	call, ok := sink.(ssa.CallInstruction)
+	if !ok {
+		return "panic"
+	}
+	c := call.Common()
+	switch {
+	case c.IsInvoke():
+		return c.Method.FullName()
+	case c.StaticCallee() != nil:
+		return c.StaticCallee().String()
+	}
+	if b, ok := c.Value.(*ssa.Builtin); ok {
+		return b.Name()
+	}
+	// Calls to function values are named by the function's signature.
+	return c.Signature().String()
+}
+
+// fingerprint identifies a finding across runs of the analyzer.
+// The ordinal distinguishes findings with the same key.
+func fingerprint(key string, ordinal int) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d", key, ordinal)))
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,325 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package levee
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+
+	"github.com/google/go-flow-levee/internal/pkg/config"
+	"github.com/google/go-flow-levee/internal/pkg/propagation"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/ssa"
+)
+
+// suggestedFixes returns a fix wrapping the tainted arguments of a finding's
+// sink in a call to the configured fix sanitizer. No fix is suggested if the
+// tainted arguments are not known, if the fix sanitizer does not sanitize
+// every label with which the sink is reached, or if some tainted argument
+// cannot be wrapped in a call to it without a type error.
+func suggestedFixes(conf *config.Config, pass *analysis.Pass, f finding) []analysis.SuggestedFix {
+	fix := conf.FixSanitizer
+	if fix == nil || len(f.args) == 0 {
+		return nil
+	}
+	for _, l := range f.labels() {
+		if !conf.IsSanitizerForLabel(fix.Package, "", fix.Method, l) {
+			return nil
+		}
+	}
+	file := enclosingFile(pass, f.sink.Pos())
+	if file == nil {
+		return nil
+	}
+	fn := sanitizerFunc(pass, fix)
+	call := sinkCallExpr(pass, f.sink.Pos())
+	if fn == nil || call == nil {
+		return nil
+	}
+	for _, arg := range f.args {
+		if !canWrap(pass, call, arg, fn.Type().(*types.Signature)) {
+			return nil
+		}
+	}
+
+	var edits []analysis.TextEdit
+	sanitizer := fix.Method
+	if fix.Package != pass.Pkg.Path() {
+		name, edit := importName(file, fn.Pkg())
+		if edit != nil {
+			edits = append(edits, *edit)
+		}
+		if name != "" {
+			sanitizer = name + "." + fix.Method
+		}
+	}
+	for _, arg := range f.args {
+		edits = append(edits,
+			analysis.TextEdit{Pos: arg.Pos(), End: arg.Pos(), NewText: []byte(sanitizer + "(")},
+			analysis.TextEdit{Pos: arg.End(), End: arg.End(), NewText: []byte(")")},
+		)
+	}
+	return []analysis.SuggestedFix{{
+		Message:   fmt.Sprintf("Sanitize with %s", sanitizer),
+		TextEdits: edits,
+	}}
+}
+
+// sanitizerFunc returns the fix sanitizer, if it is a function taking and
+// returning a single value declared by the analyzed package or one of its dependencies.
+func sanitizerFunc(pass *analysis.Pass, fix *config.FixSanitizer) *types.Func {
+	pkg := dependency(pass.Pkg, fix.Package, map[*types.Package]bool{})
+	if pkg == nil {
+		return nil
+	}
+	fn, ok := pkg.Scope().Lookup(fix.Method).(*types.Func)
+	if !ok {
+		return nil
+	}
+	sig := fn.Type().(*types.Signature)
+	if sig.Params().Len() != 1 || sig.Results().Len() != 1 || sig.Variadic() {
+		return nil
+	}
+	return fn
+}
+
+// dependency returns the package with the given import path among
+// a package and its transitive imports.
+func dependency(pkg *types.Package, importPath string, seen map[*types.Package]bool) *types.Package {
+	if pkg.Path() == importPath {
+		return pkg
+	}
+	seen[pkg] = true
+	for _, imp := range pkg.Imports() {
+		if seen[imp] {
+			continue
+		}
+		if dep := dependency(imp, importPath, seen); dep != nil {
+			return dep
+		}
+	}
+	return nil
+}
+
+// canWrap reports whether an argument of a call can be wrapped in a call to
+// a function with the given signature: the argument must be assignable to the
+// function's parameter, and its result to the parameter receiving the argument.
+func canWrap(pass *analysis.Pass, call *ast.CallExpr, arg ast.Expr, sig *types.Signature) bool {
+	t := pass.TypesInfo.TypeOf(arg)
+	param := paramType(pass, call, arg)
+	if t == nil || param == nil {
+		return false
+	}
+	return types.AssignableTo(t, sig.Params().At(0).Type()) && types.AssignableTo(sig.Results().At(0).Type(), param)
+}
+
+// paramType returns the type of the parameter receiving an argument of a call.
+// For a builtin such as panic, this is the type of its call-site signature.
+func paramType(pass *analysis.Pass, call *ast.CallExpr, arg ast.Expr) types.Type {
+	t := pass.TypesInfo.TypeOf(call.Fun)
+	if t == nil {
+		return nil
+	}
+	sig, ok := t.Underlying().(*types.Signature)
+	if !ok {
+		return nil
+	}
+	params := sig.Params().Len()
+	for i, a := range call.Args {
+		if a != arg {
+			continue
+		}
+		if sig.Variadic() && i >= params-1 && !call.Ellipsis.IsValid() {
+			return sig.Params().At(params - 1).Type().Underlying().(*types.Slice).Elem()
+		}
+		if i < params {
+			return sig.Params().At(i).Type()
+		}
+	}
+	return nil
+}
+
+// importName returns the name by which a file refers to a package.
+// If the file does not import the package, an edit adding the import
+// is also returned, and the package is referred to by its name.
+func importName(file *ast.File, pkg *types.Package) (string, *analysis.TextEdit) {
+	importPath, name := pkg.Path(), pkg.Name()
+	for _, spec := range file.Imports {
+		if p, err := strconv.Unquote(spec.Path.Value); err != nil || p != importPath {
+			continue
+		}
+		if spec.Name == nil {
+			return name, nil
+		}
+		switch spec.Name.Name {
+		case "_":
+			// The package is only imported for its side effects.
+			continue
+		case ".":
+			return "", nil
+		}
+		return spec.Name.Name, nil
+	}
+
+	quoted := strconv.Quote(importPath)
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		if gen.Lparen.IsValid() {
+			pos := gen.Lparen + 1
+			return name, &analysis.TextEdit{Pos: pos, End: pos, NewText: []byte("\n\t" + quoted)}
+		}
+		return name, &analysis.TextEdit{Pos: gen.End(), End: gen.End(), NewText: []byte("\nimport " + quoted)}
+	}
+	pos := file.Name.End()
+	return name, &analysis.TextEdit{Pos: pos, End: pos, NewText: []byte("\n\nimport " + quoted)}
+}
+
+// taintedArgExprs returns the expressions passed to a sink as its sensitive
+// arguments that are tainted by a propagation for some labels.
+// The receiver of a method is never returned, since wrapping it in a call
+// would change the method being called.
+func taintedArgExprs(pass *analysis.Pass, s propagation.Sink, prop propagation.Propagation, labels []string) []ast.Expr {
+	call := sinkCallExpr(pass, s.Instr.Pos())
+	if call == nil {
+		return nil
+	}
+	var exprs []ast.Expr
+	for _, l := range labels {
+		for _, i := range prop.TaintedSinkArgs(s, l) {
+			exprs = appendExprs(exprs, argExprs(call, s, i, prop)...)
+		}
+	}
+	return exprs
+}
+
+// argExprs returns the expressions passed to a sink as the argument at
+// a given position. For a variadic argument, these are the tainted elements.
+func argExprs(call *ast.CallExpr, s propagation.Sink, i int, prop propagation.Propagation) []ast.Expr {
+	pos := i
+	if c, ok := s.Instr.(ssa.CallInstruction); ok {
+		sig := c.Common().Signature()
+		params := sig.Params().Len()
+		// The receiver, if it is among the sink's arguments, precedes the parameters.
+		pos -= len(s.Args) - params
+		if pos < 0 {
+			return nil
+		}
+		if sig.Variadic() && pos == params-1 && !call.Ellipsis.IsValid() {
+			return variadicArgExprs(call, pos, s.Args[i], prop)
+		}
+		// The arguments may be the results of a single call, e.g. f(g()).
+		if len(call.Args) != params {
+			return nil
+		}
+	}
+	if pos >= len(call.Args) {
+		return nil
+	}
+	return []ast.Expr{call.Args[pos]}
+}
+
+// variadicArgExprs returns the tainted elements of the slice holding the
+// variadic arguments of a call, which start at a given position.
+func variadicArgExprs(call *ast.CallExpr, first int, v ssa.Value, prop propagation.Propagation) []ast.Expr {
+	slice, ok := v.(*ssa.Slice)
+	if !ok {
+		return nil
+	}
+	array, ok := slice.X.(*ssa.Alloc)
+	if !ok {
+		return nil
+	}
+	var exprs []ast.Expr
+	for _, r := range *array.Referrers() {
+		addr, ok := r.(*ssa.IndexAddr)
+		if !ok {
+			continue
+		}
+		index, ok := addr.Index.(*ssa.Const)
+		if !ok {
+			continue
+		}
+		pos := first + int(index.Int64())
+		if pos >= len(call.Args) {
+			continue
+		}
+		for _, rr := range *addr.Referrers() {
+			if store, ok := rr.(*ssa.Store); ok && prop.IsTaintedValue(store.Val) {
+				exprs = append(exprs, call.Args[pos])
+			}
+		}
+	}
+	return exprs
+}
+
+// appendExprs appends the expressions that are not already in a list.
+func appendExprs(exprs []ast.Expr, others ...ast.Expr) []ast.Expr {
+	for _, o := range others {
+		found := false
+		for _, e := range exprs {
+			if e == o {
+				found = true
+				break
+			}
+		}
+		if !found {
+			exprs = append(exprs, o)
+		}
+	}
+	return exprs
+}
+
+// sinkCallExpr returns the call expression of a sink at a given position,
+// which is that of the call's opening parenthesis, or of its go or defer statement.
+func sinkCallExpr(pass *analysis.Pass, pos token.Pos) *ast.CallExpr {
+	file := enclosingFile(pass, pos)
+	if file == nil {
+		return nil
+	}
+	path, _ := astutil.PathEnclosingInterval(file, pos, pos)
+	for _, n := range path {
+		switch t := n.(type) {
+		case *ast.CallExpr:
+			if t.Lparen == pos {
+				return t
+			}
+		case *ast.GoStmt:
+			if t.Go == pos {
+				return t.Call
+			}
+		case *ast.DeferStmt:
+			if t.Defer == pos {
+				return t.Call
+			}
+		}
+	}
+	return nil
+}
+
+func enclosingFile(pass *analysis.Pass, pos token.Pos) *ast.File {
+	for _, f := range pass.Files {
+		if f.Pos() <= pos && pos <= f.End() {
+			return f
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package levee
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-flow-levee/internal/pkg/earpointer"
+	"golang.org/x/tools/go/analysis"
+)
+
+// writeHeap writes the EAR heap of a package to a directory in DOT and JSON
+// formats, as <package>.dot and <package>.json, where the slashes of the
+// package's path are replaced with underscores. The heap is also written once
+// for each trace, with the trace's source and sink highlighted, as
+// <package>.trace<n>.dot and <package>.trace<n>.json, where the traces are
+// numbered from 1 in order of the positions of their sinks, then
+// of their sources.
+func writeHeap(dir string, pass *analysis.Pass, heap *earpointer.Partitions, traces []*earpointer.SourceSinkTrace) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("error writing EAR heap: %v", err)
+	}
+	base := packageFile(dir, pass)
+	if err := writeHeapFiles(base, heap, nil); err != nil {
+		return err
+	}
+	for i, trace := range traces {
+		if err := writeHeapFiles(fmt.Sprintf("%s.trace%d", base, i+1), heap, trace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// packageFile returns the path of a file of a directory named after the package
+// of a pass, without extension. The slashes of the package's path are replaced
+// with underscores.
+func packageFile(dir string, pass *analysis.Pass) string {
+	return filepath.Join(dir, strings.ReplaceAll(pass.Pkg.Path(), "/", "_"))
+}
+
+func writeHeapFiles(base string, heap *earpointer.Partitions, trace *earpointer.SourceSinkTrace) error {
+	j, err := heap.JSON(trace)
+	if err != nil {
+		return fmt.Errorf("error writing EAR heap: %v", err)
+	}
+	if err := ioutil.WriteFile(base+".json", j, 0666); err != nil {
+		return fmt.Errorf("error writing EAR heap: %v", err)
+	}
+	if err := ioutil.WriteFile(base+".dot", []byte(heap.DOT(trace)), 0666); err != nil {
+		return fmt.Errorf("error writing EAR heap: %v", err)
+	}
+	return nil
+}
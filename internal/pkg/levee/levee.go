@@ -15,115 +15,314 @@
 package levee
 
 import (
+	"flag"
 	"fmt"
 	"go/ast"
 	"go/token"
-	"go/types"
+	"sort"
 	"strings"
 
+	"github.com/google/go-flow-levee/internal/pkg/callees"
 	"github.com/google/go-flow-levee/internal/pkg/config"
 	"github.com/google/go-flow-levee/internal/pkg/earpointer"
+	"github.com/google/go-flow-levee/internal/pkg/fieldpropagator"
 	"github.com/google/go-flow-levee/internal/pkg/fieldtags"
+	"github.com/google/go-flow-levee/internal/pkg/paramflow"
 	"github.com/google/go-flow-levee/internal/pkg/propagation"
+	"github.com/google/go-flow-levee/internal/pkg/sarif"
 	"github.com/google/go-flow-levee/internal/pkg/source"
+	infer "github.com/google/go-flow-levee/internal/pkg/sourceinfer"
 	"github.com/google/go-flow-levee/internal/pkg/suppression"
-	"github.com/google/go-flow-levee/internal/pkg/utils"
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/ast/astutil"
-	"golang.org/x/tools/go/ssa"
 )
 
-var Analyzer = &analysis.Analyzer{
-	Name:  "levee",
-	Run:   run,
-	Flags: config.FlagSet,
-	Doc:   "reports attempts to source data to sinks",
-	Requires: []*analysis.Analyzer{
-		fieldtags.Analyzer,
-		source.Analyzer,
-		suppression.Analyzer,
-		earpointer.Analyzer,
-	},
-}
-
-func run(pass *analysis.Pass) (interface{}, error) {
-	conf, err := config.ReadConfig()
+// Analyzer reads its configuration from the file selected by the -config flag.
+var Analyzer = newAnalyzer(nil, requirements{
+	callees:         callees.Analyzer,
+	fieldTags:       fieldtags.Analyzer,
+	inferredSources: infer.Analyzer,
+	paramFlow:       paramflow.Analyzer,
+	source:          source.Analyzer,
+	earPointer:      earpointer.Analyzer,
+	earGlobals:      earpointer.GlobalsAnalyzer,
+})
+
+// NewAnalyzer returns an analyzer bound to conf, which requires a new set of
+// analyzers bound to the same configuration. If conf is nil, the analyzers
+// read their configuration as Analyzer does. Each analyzer has its own flags,
+// so analyzers write their findings to different files.
+func NewAnalyzer(conf *config.Config) *analysis.Analyzer {
+	a := NewAnalyzers(conf)[0]
+	a.Name = "levee"
+	return a
+}
+
+// NewAnalyzers returns an analyzer bound to each configuration, as NewAnalyzer does.
+//
+// The analyzers bound to the configurations are run by a single analyzer,
+// so that a driver may run the returned analyzers together, along with Analyzer.
+// To tell them apart, the analyzers are named after the order of their
+// configurations, e.g. levee1 and levee2.
+func NewAnalyzers(confs ...*config.Config) []*analysis.Analyzer {
+	graphs := make([]*boundGraph, len(confs))
+	analyzers := make([]*analysis.Analyzer, len(confs))
+	for i, conf := range confs {
+		taggedFields := fieldtags.NewAnalyzer(conf)
+		inferred := infer.NewAnalyzer(conf, taggedFields)
+		propagators := fieldpropagator.NewAnalyzer(conf, taggedFields, inferred)
+		resolved := callees.NewAnalyzer(conf)
+		sources := source.NewAnalyzer(conf, taggedFields, inferred, propagators)
+		earPointer := earpointer.NewAnalyzer(conf, resolved)
+		req := requirements{
+			callees:         resolved,
+			fieldTags:       taggedFields,
+			inferredSources: inferred,
+			paramFlow:       paramflow.NewAnalyzer(conf, resolved, taggedFields, inferred),
+			source:          sources,
+			earPointer:      earPointer,
+			earGlobals:      earpointer.NewGlobalsAnalyzer(conf, earPointer, sources, taggedFields, inferred),
+		}
+		graphs[i] = newGraph(i, taggedFields, inferred, propagators, resolved, sources, earPointer, req.paramFlow, req.earGlobals)
+		analyzers[i] = newAnalyzer(conf, req)
+		analyzers[i].Name = fmt.Sprintf("levee%d", i+1)
+	}
+	runner := newRunner(graphs)
+	for i, a := range analyzers {
+		g, run := graphs[i], a.Run
+		a.Requires = []*analysis.Analyzer{runner, suppression.Analyzer}
+		a.Run = func(pass *analysis.Pass) (interface{}, error) {
+			r := pass.ResultOf[runner].(*boundResults)
+			if err := r.errs[g]; err != nil {
+				return nil, err
+			}
+			return run(r.view(pass))
+		}
+	}
+	return analyzers
+}
+
+// requirements holds the analyzers required by a levee analyzer,
+// which are bound to the same configuration as the levee analyzer.
+type requirements struct {
+	callees         *analysis.Analyzer
+	fieldTags       *analysis.Analyzer
+	inferredSources *analysis.Analyzer
+	paramFlow       *analysis.Analyzer
+	source          *analysis.Analyzer
+	earPointer      *analysis.Analyzer
+	earGlobals      *analysis.Analyzer
+}
+
+// output holds the files to which an analyzer writes its findings,
+// as set by its flags, and the findings written so far.
+type output struct {
+	// The directory to which the findings of each package are written in SARIF format, if any.
+	sarifDir string
+	// The directory holding the fingerprints of the findings of each package that are not reported, if any.
+	baselineDir string
+	// Whether to write the findings to the baseline directory instead of reporting them.
+	writeBaseline bool
+	// The directory to which the EAR heap of each package is written, if any.
+	heapDir string
+}
+
+// A checker runs a levee analyzer.
+type checker struct {
+	conf *config.Config
+	req  requirements
+	out  *output
+	// The engines selected by the -engine flag, which is shared with the EAR pointer analysis.
+	engine *config.Engine
+}
+
+func newAnalyzer(conf *config.Config, req requirements) *analysis.Analyzer {
+	c := &checker{conf: conf, req: req, out: &output{}, engine: earpointer.EngineFlag(req.earPointer)}
+	a := &analysis.Analyzer{
+		Name:  "levee",
+		Run:   c.run,
+		Doc:   "reports attempts to source data to sinks",
+		Flags: config.NewFlagSet(conf),
+		Requires: []*analysis.Analyzer{
+			req.callees,
+			req.fieldTags,
+			req.inferredSources,
+			req.paramFlow,
+			req.source,
+			suppression.Analyzer,
+			req.earPointer,
+			req.earGlobals,
+		},
+	}
+	// In addition to the flags shared by all analyzers, the levee analyzer
+	// has the flags of the EAR pointer analysis, and flags controlling its output.
+	req.earPointer.Flags.VisitAll(func(f *flag.Flag) {
+		if a.Flags.Lookup(f.Name) == nil {
+			a.Flags.Var(f.Value, f.Name, f.Usage)
+		}
+	})
+	a.Flags.StringVar(&c.out.sarifDir, "sarif", "", "path to a directory to which the findings of each package are written in SARIF 2.1.0 format")
+	a.Flags.StringVar(&c.out.baselineDir, "baseline", "", "path to a directory holding the fingerprints of the known findings of each package, which are not reported")
+	a.Flags.BoolVar(&c.out.writeBaseline, "write-baseline", false, "write the findings of each package to the -baseline directory instead of reporting them")
+	a.Flags.StringVar(&c.out.heapDir, "ear-heap", "", "path to a directory to which the EAR heap of each package, and of each of its findings, is written in DOT and JSON formats")
+	return a
+}
+
+func (c *checker) run(pass *analysis.Pass) (interface{}, error) {
+	conf, err := config.Load(c.conf, pass.Pkg.Path())
 	if err != nil {
 		return nil, err
 	}
-	if conf.UseEAR {
-		return runEAR(pass, conf) // Use the EAR-pointer based taint analysis
-	}
-	return runPropagation(pass, conf) // Use the propagation based taint analysis
-}
-
-func runPropagation(pass *analysis.Pass, conf *config.Config) (interface{}, error) {
-	funcSources := pass.ResultOf[source.Analyzer].(source.ResultType)
-	taggedFields := pass.ResultOf[fieldtags.Analyzer].(fieldtags.ResultType)
-	suppressedNodes := pass.ResultOf[suppression.Analyzer].(suppression.ResultType)
-
-	for fn, sources := range funcSources {
-		propagations := make(map[*source.Source]propagation.Propagation, len(sources))
-		for _, s := range sources {
-			propagations[s] = propagation.Taint(s.Node, conf, taggedFields)
-		}
-
-		for _, b := range fn.Blocks {
-			for _, instr := range b.Instrs {
-				switch v := instr.(type) {
-				case *ssa.Call:
-					// TODO(#317): use more advanced call graph.
-					if callee := v.Call.StaticCallee(); callee != nil && conf.IsSink(utils.DecomposeFunction(callee)) {
-						reportSourcesReachingSink(conf, pass, suppressedNodes, propagations, instr)
-					}
-				case *ssa.Panic:
-					if conf.AllowPanicOnTaintedValues {
-						continue
-					}
-					reportSourcesReachingSink(conf, pass, suppressedNodes, propagations, instr)
-				}
+	funcSources := pass.ResultOf[c.req.source].(source.ResultType)
+	suppressions := pass.ResultOf[suppression.Analyzer].(suppression.ResultType)
+
+	usePropagation, useEAR := conf.Engines(*c.engine)
+	var engines []TaintEngine
+	if usePropagation {
+		engines = append(engines, propagationEngine{c.req}) // Use the propagation based taint analysis
+	}
+	if useEAR {
+		engines = append(engines, earEngine{c.req, c.out.heapDir}) // Use the EAR-pointer based taint analysis
+	}
+	if len(engines) == 2 && (c.out.sarifDir != "" || c.out.writeBaseline) {
+		// The differences between the engines are not findings to be recorded.
+		return nil, fmt.Errorf("-sarif and -write-baseline cannot be used when both engines are run")
+	}
+	results := make([][]finding, len(engines))
+	for i, engine := range engines {
+		if results[i], err = engine.Findings(pass, conf, funcSources); err != nil {
+			return nil, err
+		}
+	}
+	findings := results[0]
+	used := make(map[*suppression.Suppression]bool)
+	if len(engines) == 2 {
+		// In the differential mode, only the differences between the engines are reported.
+		// A suppression is used if it suppresses a finding of either engine,
+		// even if the finding is not a difference.
+		for _, fs := range results {
+			for _, f := range fs {
+				isSuppressed(conf, pass, suppressions, f, used)
 			}
 		}
+		findings = differences(engines, results)
 	}
+	return nil, c.reportFindings(conf, pass, suppressions, findings, used)
+}
 
-	return nil, nil
+// sourcesReachingSink returns a finding for the sources that reach a sink
+// through one of the sink's sensitive arguments, if there are any.
+// The sources are visited in order, so that the finding does not depend
+// on the order of the propagations' map.
+func sourcesReachingSink(pass *analysis.Pass, sources []*source.Source, propagations map[*source.Source]propagation.Propagation, sink propagation.Sink) (finding, bool) {
+	f := finding{sink: sink.Instr}
+	for _, src := range sources {
+		prop := propagations[src]
+		labels, inner := prop.ReachingLabels(sink)
+		if len(labels) == 0 {
+			continue
+		}
+		path := prop.PathToSink(sink, labels[0])
+		f.sources = append(f.sources, reachingSource{src: src, labels: labels, trace: propagationTrace(src, path), inner: inner})
+		f.args = appendExprs(f.args, taintedArgExprs(pass, sink, prop, labels)...)
+	}
+	return f, len(f.sources) > 0
 }
 
-// Use the EAR pointer analysis as the propagation engine
-func runEAR(pass *analysis.Pass, conf *config.Config) (interface{}, error) {
-	heap := pass.ResultOf[earpointer.Analyzer].(*earpointer.Partitions)
-	if heap == nil {
-		return nil, fmt.Errorf("no valid EAR partitions")
+// reportFindings reports the findings that are neither suppressed nor in the baseline,
+// in order of position. If requested, the findings are also written in SARIF format,
+// or they are written to the baseline instead of being reported. The suppressions
+// that suppress a finding are added to used, and the unused suppressions are reported.
+func (c *checker) reportFindings(conf *config.Config, pass *analysis.Pass, suppressions suppression.ResultType, findings []finding, used map[*suppression.Suppression]bool) error {
+	out := c.out
+	if out.writeBaseline && out.baselineDir == "" {
+		return fmt.Errorf("-write-baseline requires a -baseline directory to write to")
 	}
-	funcSources := pass.ResultOf[source.Analyzer].(source.ResultType)
-	taggedFields := pass.ResultOf[fieldtags.Analyzer].(fieldtags.ResultType)
-	suppressedNodes := pass.ResultOf[suppression.Analyzer].(suppression.ResultType)
-	// Return whether a field is tainted.
-	isTaintField := func(named *types.Named, index int) bool {
-		if tt, ok := named.Underlying().(*types.Struct); ok {
-			return conf.IsSourceField(utils.DecomposeField(named, index)) || taggedFields.IsSourceField(tt, index)
+	var known map[string]bool
+	if out.baselineDir != "" && !out.writeBaseline {
+		var err error
+		if known, err = readBaseline(out.baselineDir, pass); err != nil {
+			return err
 		}
-		return false
 	}
-	for _, trace := range earpointer.SourcesToSinks(funcSources, isTaintField, heap, conf) {
-		sink := trace.Sink
-		if !isSuppressed(sink.Pos(), suppressedNodes, pass) {
-			report(conf, pass, trace.Src, sink.(ssa.Node))
+	resolved := pass.ResultOf[c.req.callees].(callees.ResultType)
+	sortFindings(pass, findings)
+	var (
+		results []sarif.Result
+		entries []baselineEntry
+	)
+	ordinals := make(map[string]int)
+	for _, f := range findings {
+		// Findings with the same key are distinguished by their order of appearance.
+		key := f.key(conf, pass, resolved)
+		fingerprint := fingerprint(key, ordinals[key])
+		ordinals[key]++
+		if isSuppressed(conf, pass, suppressions, f, used) {
+			continue
+		}
+		if out.writeBaseline {
+			entries = append(entries, baselineEntry{fingerprint: fingerprint, description: describe(pass, f)})
+			continue
 		}
+		if known[fingerprint] {
+			continue
+		}
+		msg := message(conf, pass, f)
+		pass.Report(analysis.Diagnostic{
+			Pos:            f.sink.Pos(),
+			Message:        msg,
+			Related:        f.related(),
+			SuggestedFixes: suggestedFixes(conf, pass, f),
+		})
+		if out.sarifDir != "" {
+			results = append(results, sarifResult(conf, pass, resolved, f, msg, fingerprint))
+		}
+	}
+	reportSuppressions(conf, pass, suppressions, used)
+	if out.writeBaseline {
+		return writeBaseline(out.baselineDir, pass, entries)
 	}
-	return nil, nil
+	if out.sarifDir != "" {
+		if err := writeSARIF(out.sarifDir, pass, results); err != nil {
+			reportOutputError(pass, err)
+		}
+	}
+	return nil
 }
 
-func reportSourcesReachingSink(conf *config.Config, pass *analysis.Pass, suppressedNodes suppression.ResultType, propagations map[*source.Source]propagation.Propagation, sink ssa.Instruction) {
-	for src, prop := range propagations {
-		if prop.IsTainted(sink) && !isSuppressed(sink.Pos(), suppressedNodes, pass) {
-			report(conf, pass, src, sink.(ssa.Node))
-			break
+// reportOutputError reports an error writing the output of a package
+// on the package clause of its first file. Unlike an error returned by
+// the analyzer, it is not taken as a failure to analyze the package.
+func reportOutputError(pass *analysis.Pass, err error) {
+	if len(pass.Files) > 0 {
+		pass.Reportf(pass.Files[0].Package, "%v", err)
+	}
+}
+
+// isSuppressed determines whether a finding is suppressed, i.e. whether one of
+// the valid suppressions that apply to its sink covers all of its labels.
+// The suppressions that cover it are recorded as used.
+func isSuppressed(conf *config.Config, pass *analysis.Pass, suppressions suppression.ResultType, f finding, used map[*suppression.Suppression]bool) bool {
+	suppressed := false
+	for _, s := range applicableSuppressions(f.sink.Pos(), suppressions, pass) {
+		if isValid(conf, s) && s.Covers(f.labels()) {
+			used[s] = true
+			suppressed = true
 		}
 	}
+	return suppressed
+}
+
+// isValid determines whether a suppression is well-formed and,
+// if the configuration requires it, gives a reason.
+func isValid(conf *config.Config, s *suppression.Suppression) bool {
+	return s.Err == nil && (!conf.RequireSuppressionReason || s.Reason != "")
 }
 
-func isSuppressed(pos token.Pos, suppressedNodes suppression.ResultType, pass *analysis.Pass) bool {
+// applicableSuppressions returns the suppressions that apply to a sink:
+// those associated with the sink's call, those of the functions enclosing
+// the sink, and those of the sink's file.
+func applicableSuppressions(pos token.Pos, suppressions suppression.ResultType, pass *analysis.Pass) []*suppression.Suppression {
 	for _, f := range pass.Files {
 		if pos < f.Pos() || f.End() < pos {
 			continue
@@ -132,7 +331,23 @@ func isSuppressed(pos token.Pos, suppressedNodes suppression.ResultType, pass *a
 		// position, from the leaf node that directly contains it up to the ast.File node
 		path, _ := astutil.PathEnclosingInterval(f, pos, pos)
 		if len(path) < 2 {
-			return false
+			return nil
+		}
+		result := append([]*suppression.Suppression(nil), suppressions.FileSuppressions(f)...)
+		for _, n := range path {
+			switch n.(type) {
+			case *ast.FuncDecl, *ast.FuncLit:
+				result = append(result, suppressions.FunctionSuppressions(n)...)
+			}
+		}
+		// Given the position of a go or defer statement, path[0] holds the
+		// ast.GoStmt or ast.DeferStmt. Its call is handled like any other call,
+		// with the statement in place of the ast.ExprStmt.
+		switch s := path[0].(type) {
+		case *ast.GoStmt:
+			path = append([]ast.Node{s.Call}, path...)
+		case *ast.DeferStmt:
+			path = append([]ast.Node{s.Call}, path...)
 		}
 		// Given the position of a call, path[0] holds the ast.CallExpr and
 		// path[1] holds the ast.ExprStmt. A suppressing comment may be associated
@@ -144,31 +359,68 @@ func isSuppressed(pos token.Pos, suppressedNodes suppression.ResultType, pass *a
 				/*
 					Sink( // levee.DoNotReport
 				*/
-				if suppressedNodes.IsSuppressed(t) {
-					return true
-				}
+				result = append(result, suppressions.NodeSuppressions(t)...)
 			case *ast.SelectorExpr:
 				/*
 					core.Sink( // levee.DoNotReport
 				*/
-				if suppressedNodes.IsSuppressed(t.Sel) {
-					return true
-				}
+				result = append(result, suppressions.NodeSuppressions(t.Sel)...)
 			}
 		} else {
 			fmt.Printf("unexpected node received: %v (type %T); please report this issue\n", path[0], path[0])
 		}
-		return suppressedNodes.IsSuppressed(path[0]) || suppressedNodes.IsSuppressed(path[1])
+		result = append(result, suppressions.NodeSuppressions(path[0])...)
+		return append(result, suppressions.NodeSuppressions(path[1])...)
+	}
+	return nil
+}
+
+// reportSuppressions reports the suppressions that are not valid and,
+// if the configuration requests it, those that do not suppress any finding.
+func reportSuppressions(conf *config.Config, pass *analysis.Pass, suppressions suppression.ResultType, used map[*suppression.Suppression]bool) {
+	for _, s := range suppressions.All {
+		switch {
+		case s.Err != nil:
+			pass.Reportf(s.Pos, "%v", s.Err)
+		case !isValid(conf, s):
+			pass.Reportf(s.Pos, "suppression is missing a reason, e.g. levee.DoNotReport(reason=\"...\")")
+		case conf.ReportUnusedSuppressions && !used[s]:
+			pass.Reportf(s.Pos, "suppression does not suppress any finding")
+		}
 	}
-	return false
 }
 
-func report(conf *config.Config, pass *analysis.Pass, source *source.Source, sink ssa.Node) {
+// message describes a finding.
+func message(conf *config.Config, pass *analysis.Pass, f finding) string {
 	var b strings.Builder
 	b.WriteString("a source has reached a sink")
-	fmt.Fprintf(&b, "\n source: %v", pass.Fset.Position(source.Pos()))
+	if f.onlyBy != "" {
+		fmt.Fprintf(&b, ", according to the %s engine only", f.onlyBy)
+	}
+	for _, rs := range f.sources {
+		fmt.Fprintf(&b, "\n source: %v", pass.Fset.Position(rs.src.Pos()))
+	}
+	for _, inner := range f.innerSinks() {
+		fmt.Fprintf(&b, "\n sink: %v", inner)
+	}
+	if named := namedLabels(f.labels()); len(named) > 0 {
+		fmt.Fprintf(&b, "\n label: %v", strings.Join(named, ", "))
+	}
 	if conf.ReportMessage != "" {
 		fmt.Fprintf(&b, "\n %v", conf.ReportMessage)
 	}
-	pass.Reportf(sink.Pos(), b.String())
+	return b.String()
+}
+
+// namedLabels returns the sorted labels other than the default label,
+// since sources that are not explicitly labeled are not named.
+func namedLabels(labels []string) []string {
+	var named []string
+	for _, l := range labels {
+		if l != config.DefaultLabel {
+			named = append(named, l)
+		}
+	}
+	sort.Strings(named)
+	return named
 }
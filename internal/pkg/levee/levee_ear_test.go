@@ -15,8 +15,13 @@
 package levee
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-flow-levee/internal/pkg/debug"
 	"golang.org/x/tools/go/analysis/analysistest"
 )
@@ -44,9 +49,11 @@ func TestLeveeEAR(t *testing.T) {
 	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/example/tests/excludedpackage")
 	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/example/tests/extracts")
 	// analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/example/tests/fields")  // TODO: FP has been fixed?
+	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/example/tests/godefer")
 	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/example/tests/includedpackage")
 	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/example/tests/inlining")
 	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/example/tests/loops")
+	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/example/tests/multiplesources")
 
 	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/example/tests/namedreturn")
 	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/example/tests/panic")
@@ -59,7 +66,7 @@ func TestLeveeEAR(t *testing.T) {
 
 	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/example/tests/receivers")
 	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/example/tests/recover")
-	// analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/example/tests/sanitization")  // TODO: santizers are not handled yet
+	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/example/tests/sanitization")
 	// analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/example/tests/sinks")  // TODO
 	// analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/example/tests/store")  // TODO: flow sensitive
 	// analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/example/tests/structlit")  // TODO: NP have been fixed?
@@ -74,3 +81,174 @@ func TestLeveeEARInter(t *testing.T) {
 
 	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/ear/tests/...")
 }
+
+func TestLeveeEARSummaries(t *testing.T) {
+	dataDir := analysistest.TestData()
+	if err := Analyzer.Flags.Set("config", dataDir+"/summaries-ear-config.yaml"); err != nil {
+		t.Error(err)
+	}
+
+	// Interface method calls have no callees in the static call graph used by EAR,
+	// so only statically dispatched calls are tested here.
+	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/summaries.com/tests/static")
+}
+
+func TestLeveeEARSinkArgs(t *testing.T) {
+	dataDir := analysistest.TestData()
+	if err := Analyzer.Flags.Set("config", dataDir+"/sinkargs-ear-config.yaml"); err != nil {
+		t.Error(err)
+	}
+
+	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/sinkargs.com/...")
+}
+
+func TestLeveeEARSourceFunctions(t *testing.T) {
+	dataDir := analysistest.TestData()
+	if err := Analyzer.Flags.Set("config", dataDir+"/sourcefuncs-ear-config.yaml"); err != nil {
+		t.Error(err)
+	}
+
+	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/sourcefuncs.com/...")
+}
+
+func TestLeveeEARLabels(t *testing.T) {
+	dataDir := analysistest.TestData()
+	if err := Analyzer.Flags.Set("config", dataDir+"/labels-ear-config.yaml"); err != nil {
+		t.Error(err)
+	}
+
+	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/labels.com/tests/sinks", "./src/levee_analysistest/labels.com/tests/sanitizers")
+}
+
+func TestLeveeEARCallGraphs(t *testing.T) {
+	for _, cg := range []string{"cha", "rta", "vta"} {
+		t.Run(cg, func(t *testing.T) {
+			dataDir := analysistest.TestData()
+			if err := Analyzer.Flags.Set("config", dataDir+"/callgraph-ear-"+cg+"-config.yaml"); err != nil {
+				t.Error(err)
+			}
+			analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/callgraph.com/tests/ear")
+		})
+	}
+}
+
+func TestLeveeEARCallGraphTrace(t *testing.T) {
+	dataDir := analysistest.TestData()
+	if err := Analyzer.Flags.Set("config", dataDir+"/callgraph-ear-vta-config.yaml"); err != nil {
+		t.Error(err)
+	}
+	results := analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/callgraph.com/tests/ear")
+
+	// Function values are named after the variable or field holding them,
+	// or else described by their signature.
+	want := []string{
+		"31: source",
+		"33: through the call to Forward",
+		"40: source",
+		"41: through the call to apply",
+		"37: through the call to f",
+		"50: source",
+		"54: through the call to handle",
+		"57: source",
+		"61: through the call to a function of type func(interface{})",
+	}
+	if diff := cmp.Diff(want, relatedSteps(results)); diff != "" {
+		t.Errorf("related information diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestLeveeEARCrossPackage(t *testing.T) {
+	dataDir := analysistest.TestData()
+	if err := Analyzer.Flags.Set("config", dataDir+"/crosspkg-ear-config.yaml"); err != nil {
+		t.Error(err)
+	}
+
+	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/crosspkg.com/tests")
+}
+
+func TestLeveeEARValidators(t *testing.T) {
+	dataDir := analysistest.TestData()
+	if err := Analyzer.Flags.Set("config", dataDir+"/validators-ear-config.yaml"); err != nil {
+		t.Error(err)
+	}
+
+	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/validators.com/...")
+}
+
+func TestLeveeEARSourceInference(t *testing.T) {
+	dataDir := analysistest.TestData()
+	if err := Analyzer.Flags.Set("config", dataDir+"/inference-ear-config.yaml"); err != nil {
+		t.Error(err)
+	}
+
+	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/inference.com/tests/enabled", "./src/levee_analysistest/inference.com/tests/propagators")
+}
+
+func TestLeveeEARTrace(t *testing.T) {
+	dataDir := analysistest.TestData()
+	if err := Analyzer.Flags.Set("config", dataDir+"/test-ear-config.yaml"); err != nil {
+		t.Error(err)
+	}
+	results := analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/trace.com/ear")
+
+	want := []string{
+		"21: source",
+		"22: through the call to sinkValue",
+	}
+	if diff := cmp.Diff(want, relatedSteps(results)); diff != "" {
+		t.Errorf("related information diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestLeveeEARHeap(t *testing.T) {
+	dataDir := analysistest.TestData()
+	if err := Analyzer.Flags.Set("config", dataDir+"/test-ear-config.yaml"); err != nil {
+		t.Error(err)
+	}
+	dir, err := ioutil.TempDir("", "levee")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	a := NewAnalyzer(nil)
+	if err := a.Flags.Set("ear-heap", dir); err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, dataDir, a, "./src/levee_analysistest/trace.com/ear")
+
+	var files []string
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, info := range infos {
+		files = append(files, info.Name())
+	}
+	// The source of the test reaches the sink along with the interface
+	// value made from it, hence two traces.
+	base := "levee_analysistest_trace.com_ear"
+	want := []string{
+		base + ".dot",
+		base + ".json",
+		base + ".trace1.dot",
+		base + ".trace1.json",
+		base + ".trace2.dot",
+		base + ".trace2.json",
+	}
+	if diff := cmp.Diff(want, files); diff != "" {
+		t.Errorf("files diff (-want +got):\n%s", diff)
+	}
+	// Only the heap of the trace highlights its source and its sink.
+	for file, highlighted := range map[string]bool{base + ".dot": false, base + ".trace1.dot": true, base + ".trace2.dot": true} {
+		b, err := ioutil.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, mark := range []string{"(source)", "(sink)"} {
+			if got := strings.Contains(string(b), mark); got != highlighted {
+				t.Errorf("%s contains %q: got %v, want %v", file, mark, got, highlighted)
+			}
+		}
+	}
+}
@@ -15,10 +15,20 @@
 package levee
 
 import (
+	"encoding/json"
 	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-flow-levee/internal/pkg/config"
 	"github.com/google/go-flow-levee/internal/pkg/debug"
+	"github.com/google/go-flow-levee/internal/pkg/sarif"
+	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/analysistest"
 )
 
@@ -61,3 +71,427 @@ func TestFormattingWithoutCustomReportMessage(t *testing.T) {
 	}
 	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/custom.message.com/nocustom")
 }
+
+func TestSummaries(t *testing.T) {
+	dataDir := analysistest.TestData()
+	if err := Analyzer.Flags.Set("config", dataDir+"/summaries-config.yaml"); err != nil {
+		t.Error(err)
+	}
+	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/summaries.com/...")
+}
+
+func TestSinkArgs(t *testing.T) {
+	dataDir := analysistest.TestData()
+	if err := Analyzer.Flags.Set("config", dataDir+"/sinkargs-config.yaml"); err != nil {
+		t.Error(err)
+	}
+	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/sinkargs.com/...")
+}
+
+func TestSourceFunctions(t *testing.T) {
+	dataDir := analysistest.TestData()
+	if err := Analyzer.Flags.Set("config", dataDir+"/sourcefuncs-config.yaml"); err != nil {
+		t.Error(err)
+	}
+	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/sourcefuncs.com/...")
+}
+
+func TestLabels(t *testing.T) {
+	dataDir := analysistest.TestData()
+	if err := Analyzer.Flags.Set("config", dataDir+"/labels-config.yaml"); err != nil {
+		t.Error(err)
+	}
+	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/labels.com/...")
+}
+
+func TestValidators(t *testing.T) {
+	dataDir := analysistest.TestData()
+	if err := Analyzer.Flags.Set("config", dataDir+"/validators-config.yaml"); err != nil {
+		t.Error(err)
+	}
+	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/validators.com/...")
+}
+
+func TestSourceInference(t *testing.T) {
+	dataDir := analysistest.TestData()
+	if err := Analyzer.Flags.Set("config", dataDir+"/inference-config.yaml"); err != nil {
+		t.Error(err)
+	}
+	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/inference.com/tests/enabled", "./src/levee_analysistest/inference.com/tests/propagators")
+}
+
+func TestSourceInferenceDisabled(t *testing.T) {
+	dataDir := analysistest.TestData()
+	if err := Analyzer.Flags.Set("config", dataDir+"/inference-disabled-config.yaml"); err != nil {
+		t.Error(err)
+	}
+	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/inference.com/tests/disabled")
+}
+
+func TestInterprocedural(t *testing.T) {
+	dataDir := analysistest.TestData()
+	if err := Analyzer.Flags.Set("config", dataDir+"/interproc-config.yaml"); err != nil {
+		t.Error(err)
+	}
+	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/interproc.com/...")
+}
+
+func TestStaticCallGraph(t *testing.T) {
+	dataDir := analysistest.TestData()
+	if err := Analyzer.Flags.Set("config", dataDir+"/callgraph-config.yaml"); err != nil {
+		t.Error(err)
+	}
+	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/callgraph.com/tests/static")
+}
+
+func TestCallGraphs(t *testing.T) {
+	for _, cg := range []string{"cha", "rta", "vta"} {
+		t.Run(cg, func(t *testing.T) {
+			dataDir := analysistest.TestData()
+			if err := Analyzer.Flags.Set("config", dataDir+"/callgraph-"+cg+"-config.yaml"); err != nil {
+				t.Error(err)
+			}
+			analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/callgraph.com/tests/dynamic")
+		})
+	}
+}
+
+func TestOverlays(t *testing.T) {
+	dataDir := analysistest.TestData()
+	if err := Analyzer.Flags.Set("config", dataDir+"/overlay-config.yaml"); err != nil {
+		t.Error(err)
+	}
+	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/overlay.com/...")
+}
+
+func TestSuppressions(t *testing.T) {
+	dataDir := analysistest.TestData()
+	if err := Analyzer.Flags.Set("config", dataDir+"/suppression-config.yaml"); err != nil {
+		t.Error(err)
+	}
+	analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/suppression.com/...")
+}
+
+func TestSuggestedFixes(t *testing.T) {
+	dataDir := analysistest.TestData()
+	if err := Analyzer.Flags.Set("config", dataDir+"/fix-config.yaml"); err != nil {
+		t.Error(err)
+	}
+	analysistest.RunWithSuggestedFixes(t, dataDir, Analyzer, "./src/levee_analysistest/fix.com/...")
+}
+
+func TestSuggestedFixesWithTypedSanitizer(t *testing.T) {
+	dataDir := analysistest.TestData()
+	if err := Analyzer.Flags.Set("config", dataDir+"/typedfix-config.yaml"); err != nil {
+		t.Error(err)
+	}
+	analysistest.RunWithSuggestedFixes(t, dataDir, Analyzer, "./src/levee_analysistest/typedfix.com/...")
+}
+
+func TestSuggestedFixesWithVersionedSanitizerPackage(t *testing.T) {
+	dataDir := analysistest.TestData()
+	if err := Analyzer.Flags.Set("config", dataDir+"/versionedfix-config.yaml"); err != nil {
+		t.Error(err)
+	}
+	analysistest.RunWithSuggestedFixes(t, dataDir, Analyzer, "./src/levee_analysistest/versionedfix.com/tests")
+}
+
+func TestBaseline(t *testing.T) {
+	dataDir := analysistest.TestData()
+	if err := Analyzer.Flags.Set("config", dataDir+"/test-config.yaml"); err != nil {
+		t.Error(err)
+	}
+	analysistest.Run(t, dataDir, baselineAnalyzer(t, dataDir+"/baseline", false), "./src/levee_analysistest/baseline.com/tests")
+}
+
+func TestBaselineDoesNotDependOnConfigurationOrder(t *testing.T) {
+	dataDir := analysistest.TestData()
+	if err := Analyzer.Flags.Set("config", dataDir+"/baseline-reordered-config.yaml"); err != nil {
+		t.Error(err)
+	}
+	analysistest.Run(t, dataDir, baselineAnalyzer(t, dataDir+"/baseline", false), "./src/levee_analysistest/baseline.com/tests")
+}
+
+func TestWriteBaseline(t *testing.T) {
+	dataDir := analysistest.TestData()
+	if err := Analyzer.Flags.Set("config", dataDir+"/test-config.yaml"); err != nil {
+		t.Error(err)
+	}
+	dir, err := ioutil.TempDir("", "levee")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// When writing a baseline, findings are not reported.
+	// Each package is written to its own file, as happens when the packages
+	// are analyzed by separate processes.
+	analysistest.Run(t, dataDir, baselineAnalyzer(t, dir, true), "./src/levee_analysistest/baseline.com/known")
+	analysistest.Run(t, dataDir, baselineAnalyzer(t, dir, true), "./src/levee_analysistest/baseline.com/alsoknown")
+	b, err := ioutil.ReadFile(filepath.Join(dir, "levee_analysistest_baseline.com_known.baseline"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.Count(string(b), "\n"), 5; got != want {
+		t.Errorf("got %d lines in the baseline, want %d:\n%s", got, want, b)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "levee_analysistest_baseline.com_alsoknown.baseline")); err != nil {
+		t.Error(err)
+	}
+
+	// Once they are in the baseline, findings are not reported either.
+	analysistest.Run(t, dataDir, baselineAnalyzer(t, dir, false), "./src/levee_analysistest/baseline.com/known")
+	analysistest.Run(t, dataDir, baselineAnalyzer(t, dir, false), "./src/levee_analysistest/baseline.com/alsoknown")
+}
+
+// baselineAnalyzer returns a new analyzer with the given baseline flags.
+func baselineAnalyzer(t *testing.T, dir string, write bool) *analysis.Analyzer {
+	a := NewAnalyzer(nil)
+	if err := a.Flags.Set("baseline", dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Flags.Set("write-baseline", fmt.Sprint(write)); err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+// boundConfigs returns configurations whose sources are the credentials and
+// the addresses of the bound.com testdata, respectively.
+func boundConfigs(t *testing.T) (credentials, addresses *config.Config) {
+	sink := config.SinkMatcher{FuncMatcher: config.FuncMatcher{
+		Package: config.Literal("levee_analysistest/bound.com/core"),
+		Method:  config.Literal("Sink"),
+	}}
+	addressRE, err := config.Regexp("Address$")
+	if err != nil {
+		t.Fatal(err)
+	}
+	credentials = &config.Config{
+		Sources: []config.SourceMatcher{{
+			Package: config.Literal("levee_analysistest/bound.com/core"),
+			Type:    config.Literal("Credentials"),
+		}},
+		Sinks: []config.SinkMatcher{sink},
+	}
+	addresses = &config.Config{
+		Sources: []config.SourceMatcher{{
+			Package: config.Literal("levee_analysistest/bound.com/core"),
+			Type:    addressRE,
+		}},
+		Sinks: []config.SinkMatcher{sink},
+	}
+	return credentials, addresses
+}
+
+func TestNewAnalyzer(t *testing.T) {
+	credentials, addresses := boundConfigs(t)
+
+	// Each analyzer only uses its own configuration.
+	dataDir := analysistest.TestData()
+	analysistest.Run(t, dataDir, NewAnalyzer(credentials), "./src/levee_analysistest/bound.com/credentials")
+	analysistest.Run(t, dataDir, NewAnalyzer(addresses), "./src/levee_analysistest/bound.com/addresses")
+}
+
+func TestBoundAnalyzersRunTogether(t *testing.T) {
+	credentials, addresses := boundConfigs(t)
+	analyzers := NewAnalyzers(credentials, addresses)
+	if analyzers[0].Name == analyzers[1].Name {
+		t.Errorf("both analyzers are named %s", analyzers[0].Name)
+	}
+	if err := analysis.Validate(append([]*analysis.Analyzer{Analyzer}, analyzers...)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Since the first analyzer requires the second one, both are run by the
+	// same driver, and each analyzer still only uses its own configuration.
+	dataDir := analysistest.TestData()
+	analyzers[0].Requires = append(analyzers[0].Requires, analyzers[1])
+	analysistest.Run(t, dataDir, analyzers[0], "./src/levee_analysistest/bound.com/credentials")
+	analysistest.Run(t, dataDir, analyzers[1], "./src/levee_analysistest/bound.com/addresses")
+}
+
+func TestNewAnalyzerDoesNotChangeOtherAnalyzers(t *testing.T) {
+	credentials, addresses := boundConfigs(t)
+	a := NewAnalyzer(credentials)
+	runner := a.Requires[0]
+	requires := analyzerNames(runner.Requires)
+	NewAnalyzer(addresses)
+	if diff := cmp.Diff(requires, analyzerNames(runner.Requires)); diff != "" {
+		t.Errorf("the requirements of the runner of an analyzer changed (-before +after):\n%s", diff)
+	}
+}
+
+func analyzerNames(analyzers []*analysis.Analyzer) []string {
+	var names []string
+	for _, a := range analyzers {
+		names = append(names, a.Name)
+	}
+	return names
+}
+
+func TestSARIF(t *testing.T) {
+	dataDir := analysistest.TestData()
+	if err := Analyzer.Flags.Set("config", dataDir+"/test-config.yaml"); err != nil {
+		t.Error(err)
+	}
+	dir, err := ioutil.TempDir("", "levee")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	sarifPath := filepath.Join(dir, "levee_analysistest_example_tests_sarif.sarif")
+
+	var runs []*sarif.Log
+	for i := 0; i < 2; i++ {
+		a := NewAnalyzer(nil)
+		if err := a.Flags.Set("sarif", dir); err != nil {
+			t.Fatal(err)
+		}
+		analysistest.Run(t, dataDir, a, "./src/levee_analysistest/example/tests/sarif")
+		b, err := ioutil.ReadFile(sarifPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var log sarif.Log
+		if err := json.Unmarshal(b, &log); err != nil {
+			t.Fatal(err)
+		}
+		runs = append(runs, &log)
+	}
+
+	log := runs[0]
+	if log.Version != sarif.Version || len(log.Runs) != 1 {
+		t.Fatalf("got version %q with %d runs, want version %q with 1 run", log.Version, len(log.Runs), sarif.Version)
+	}
+	results := log.Runs[0].Results
+	if len(results) != 4 {
+		t.Fatalf("got %d results, want 4", len(results))
+	}
+
+	first := results[0]
+	if first.RuleID != ruleID {
+		t.Errorf("got rule id %q, want %q", first.RuleID, ruleID)
+	}
+	uri := "testdata/src/levee_analysistest/example/tests/sarif/tests.go"
+	if got := first.Locations[0].PhysicalLocation; got.ArtifactLocation.URI != uri || got.Region.StartLine != 22 {
+		t.Errorf("got sink location %v, want %s:22", got, uri)
+	}
+	if got := first.RelatedLocations[0].PhysicalLocation; got.ArtifactLocation.URI != uri || got.Region.StartLine != 21 {
+		t.Errorf("got source location %v, want %s:21", got, uri)
+	}
+	flow := first.CodeFlows[0].ThreadFlows[0].Locations
+	if got := flow[0].Location.Message.Text; got != "source" {
+		t.Errorf("got code flow starting with %q, want source", got)
+	}
+	if got := flow[len(flow)-1].Location.Message.Text; got != "sink" {
+		t.Errorf("got code flow ending with %q, want sink", got)
+	}
+	if got, want := first.Properties["configEntries"], []interface{}{"Sources[0]", "Sinks[0]"}; !cmp.Equal(got, want) {
+		t.Errorf("got config entries %v, want %v", got, want)
+	}
+
+	fingerprints := make(map[string]bool)
+	for i, r := range results {
+		fp := r.PartialFingerprints[fingerprintKey]
+		if fingerprints[fp] {
+			t.Errorf("result %d has a duplicate fingerprint %q", i, fp)
+		}
+		fingerprints[fp] = true
+		if other := runs[1].Runs[0].Results[i].PartialFingerprints[fingerprintKey]; other != fp {
+			t.Errorf("result %d has fingerprint %q in a later run, want %q", i, other, fp)
+		}
+	}
+}
+
+func TestTrace(t *testing.T) {
+	dataDir := analysistest.TestData()
+	if err := Analyzer.Flags.Set("config", dataDir+"/test-config.yaml"); err != nil {
+		t.Error(err)
+	}
+	results := analysistest.Run(t, dataDir, Analyzer, "./src/levee_analysistest/trace.com/propagation")
+
+	want := []string{
+		"23: source",
+		"24: loaded",
+		"24: stored in an array",
+		"24: through the call to Sprintf",
+		"26: sent on a channel",
+		"25: stored in a channel",
+		"27: received from a channel",
+		"27: stored in an array",
+	}
+	if diff := cmp.Diff(want, relatedSteps(results)); diff != "" {
+		t.Errorf("related information diff (-want +got):\n%s", diff)
+	}
+}
+
+// relatedSteps describes the related information of the diagnostics
+// reported for a package, by line and message.
+func relatedSteps(results []*analysistest.Result) []string {
+	var steps []string
+	for _, d := range results[0].Diagnostics {
+		for _, r := range d.Related {
+			steps = append(steps, fmt.Sprintf("%d: %s", results[0].Pass.Fset.Position(r.Pos).Line, r.Message))
+		}
+	}
+	return steps
+}
+
+func TestEngines(t *testing.T) {
+	dataDir := analysistest.TestData()
+	if err := Analyzer.Flags.Set("config", dataDir+"/engines-config.yaml"); err != nil {
+		t.Error(err)
+	}
+	a := NewAnalyzer(nil)
+	if err := a.Flags.Set("engine", "both"); err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, dataDir, a, "./src/levee_analysistest/engines.com/tests")
+}
+
+func TestEnginesRefuseOutputs(t *testing.T) {
+	dataDir := analysistest.TestData()
+	if err := Analyzer.Flags.Set("config", dataDir+"/engines-config.yaml"); err != nil {
+		t.Error(err)
+	}
+	dir, err := ioutil.TempDir("", "levee")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, flags := range []map[string]string{
+		{"sarif": dir},
+		{"baseline": dir, "write-baseline": "true"},
+	} {
+		a := NewAnalyzer(nil)
+		flags["engine"] = "both"
+		for name, value := range flags {
+			if err := a.Flags.Set(name, value); err != nil {
+				t.Fatal(err)
+			}
+		}
+		// The differences between the engines are neither written nor reported.
+		var errs errorRecorder
+		analysistest.Run(&errs, dataDir, a, "./src/levee_analysistest/engines.com/tests")
+		if !errs.contains("cannot be used when both engines are run") {
+			t.Errorf("with flags %v, got errors %q, want an error refusing the flags", flags, errs)
+		}
+	}
+}
+
+// errorRecorder records the errors of an analysistest run.
+type errorRecorder []string
+
+func (r *errorRecorder) Errorf(format string, args ...interface{}) {
+	*r = append(*r, fmt.Sprintf(format, args...))
+}
+
+func (r errorRecorder) contains(s string) bool {
+	for _, err := range r {
+		if strings.Contains(err, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package levee
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/google/go-flow-levee/internal/pkg/callees"
+	"github.com/google/go-flow-levee/internal/pkg/config"
+	"github.com/google/go-flow-levee/internal/pkg/sarif"
+	"github.com/google/go-flow-levee/internal/pkg/utils"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ssa"
+)
+
+// fingerprintKey names the fingerprint of a finding in a SARIF result.
+const fingerprintKey = "leveeFingerprint/v1"
+
+var driver = sarif.Driver{
+	Name:           "levee",
+	InformationURI: "https://github.com/google/go-flow-levee",
+	Rules: []sarif.Rule{{
+		ID:               ruleID,
+		ShortDescription: &sarif.Message{Text: "a source has reached a sink"},
+		FullDescription:  &sarif.Message{Text: "Data from a configured source reaches a configured sink without being sanitized."},
+	}},
+}
+
+// writeSARIF writes the results of a package to a directory as a SARIF log,
+// named after the package as <package>.sarif. Each package has its own log,
+// so that the output does not depend on whether packages are analyzed in
+// a single process. If the package has no results, its log is removed,
+// so that the directory does not hold the results of a previous run.
+func writeSARIF(dir string, pass *analysis.Pass, results []sarif.Result) error {
+	file := packageFile(dir, pass) + ".sarif"
+	if len(results) == 0 {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error writing SARIF output: %v", err)
+		}
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("error writing SARIF output: %v", err)
+	}
+	root, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("error writing SARIF output: %v", err)
+	}
+	if err := sarif.NewLog(driver, root, results).WriteFile(file); err != nil {
+		return fmt.Errorf("error writing SARIF output: %v", err)
+	}
+	return nil
+}
+
+// sarifResult describes a finding as a SARIF result. The sink is the location
+// of the result, each source is a related location, and the path from each
+// source to the sink is a code flow.
+func sarifResult(conf *config.Config, pass *analysis.Pass, resolved callees.ResultType, f finding, msg, fingerprint string) sarif.Result {
+	root, _ := os.Getwd()
+	sinkPos := pass.Fset.Position(f.sink.Pos())
+
+	// Each source is a related location, with its own code flow.
+	var (
+		related []sarif.Location
+		flows   []sarif.CodeFlow
+	)
+	for _, rs := range f.sources {
+		related = append(related, sarif.NewLocation(pass.Fset.Position(rs.src.Pos()), root, "source"))
+		var flow []sarif.ThreadFlowLocation
+		for _, s := range rs.trace {
+			flow = append(flow, sarif.ThreadFlowLocation{Location: sarif.NewLocation(pass.Fset.Position(s.pos), root, s.what)})
+		}
+		if rs.inner.IsValid() {
+			flow = append(flow, sarif.ThreadFlowLocation{Location: sarif.NewLocation(rs.inner, root, "sink reached within the called function")})
+		}
+		flows = append(flows, sarif.CodeFlow{ThreadFlows: []sarif.ThreadFlow{{Locations: flow}}})
+	}
+
+	properties := map[string]interface{}{"configEntries": f.configEntries(conf, resolved)}
+	if named := namedLabels(f.labels()); len(named) > 0 {
+		properties["labels"] = named
+	}
+
+	return sarif.Result{
+		RuleID:              ruleID,
+		Level:               "error",
+		Message:             sarif.Message{Text: msg},
+		Locations:           []sarif.Location{sarif.NewLocation(sinkPos, root, "")},
+		RelatedLocations:    related,
+		CodeFlows:           flows,
+		PartialFingerprints: map[string]string{fingerprintKey: fingerprint},
+		Properties:          properties,
+	}
+}
+
+// sinkEntries returns the names of the configured sinks matching the functions
+// that may be called by a sink. Panics do not match any configured sink.
+func sinkEntries(conf *config.Config, resolved callees.ResultType, sink ssa.Instruction) []string {
+	call, ok := sink.(ssa.CallInstruction)
+	if !ok {
+		return nil
+	}
+	var entries []string
+	seen := make(map[string]bool)
+	for _, callee := range resolved.Callees(call) {
+		for _, e := range conf.SinkEntries(utils.DecomposeFunction(callee)) {
+			if !seen[e] {
+				seen[e] = true
+				entries = append(entries, e)
+			}
+		}
+	}
+	return entries
+}
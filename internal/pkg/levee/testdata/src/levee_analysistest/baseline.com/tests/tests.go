@@ -0,0 +1,48 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"levee_analysistest/example/core"
+)
+
+func TestKnownFinding(s core.Source) {
+	core.Sink(s)
+}
+
+func TestKnownFindingMoved(s core.Source) {
+
+	// The finding is known even though it is no longer on the same line.
+	core.Sink(s)
+}
+
+// The finding is known whether the source is held by a value or by a pointer.
+func TestKnownFindingOfPointer(s *core.Source) {
+	core.Sink(s)
+}
+
+func TestNewFinding(s core.Source) {
+	core.Sink(s) // want "a source has reached a sink"
+}
+
+func TestNewSinkInKnownFunction(s core.Source) {
+	core.Sink(s)
+	core.Sinkf("%v", s) // want "a source has reached a sink"
+}
+
+func TestSameSinkAgainInKnownFunction(s core.Source) {
+	core.Sink(s)
+	core.Sink(s) // want "a source has reached a sink"
+}
@@ -0,0 +1,41 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dynamic
+
+import (
+	"levee_analysistest/callgraph.com/core"
+)
+
+var logFn func(args ...interface{}) = core.Sink
+
+func TestInterfaceMethodCallResolvedToSink(s core.Source) {
+	var l core.Logger = &core.StdoutLogger{}
+	l.Log(s.Data) // want "a source has reached a sink"
+}
+
+func TestFunctionValueCallResolvedToSink(s core.Source) {
+	logFn(s.Data) // want "a source has reached a sink"
+}
+
+func TestMethodValueCallResolvedToSink(s core.Source) {
+	log := (&core.StdoutLogger{}).Log
+	log(s.Data) // want "a source has reached a sink"
+}
+
+func TestInterfaceMethodCallResolvedToSanitizer(s *core.Source) {
+	var r core.Redactor = core.Masker{}
+	r.Redact(s)
+	core.Sink(s)
+}
@@ -0,0 +1,62 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ear
+
+import (
+	"levee_analysistest/callgraph.com/core"
+)
+
+type forwarder interface {
+	Forward(x interface{})
+}
+
+type sinkForwarder struct{}
+
+func (sinkForwarder) Forward(x interface{}) {
+	core.Sink(x) // want "a source has reached a sink"
+}
+
+func TestInterfaceMethodParameterIsUnifiedWithArgument(s *core.Source) {
+	var f forwarder = sinkForwarder{}
+	f.Forward(s)
+}
+
+func apply(f func(interface{}), x interface{}) {
+	f(x)
+}
+
+func TestClosureParameterIsUnifiedWithArgument(s *core.Source) {
+	apply(func(x interface{}) {
+		core.Sink(x) // want "a source has reached a sink"
+	}, s)
+}
+
+type handler struct {
+	handle func(interface{})
+}
+
+func TestFieldClosureParameterIsUnifiedWithArgument(s *core.Source) {
+	h := &handler{handle: func(x interface{}) {
+		core.Sink(x) // want "a source has reached a sink"
+	}}
+	h.handle(s)
+}
+
+func TestElementClosureParameterIsUnifiedWithArgument(s *core.Source) {
+	handlers := []func(interface{}){func(x interface{}) {
+		core.Sink(x) // want "a source has reached a sink"
+	}}
+	handlers[0](s)
+}
@@ -0,0 +1,52 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cache holds values for other packages.
+package cache
+
+import (
+	"levee_analysistest/crosspkg.com/core"
+)
+
+var (
+	Last  interface{}
+	Count interface{}
+)
+
+type Box struct {
+	v interface{}
+}
+
+func (b *Box) Put(v interface{}) {
+	b.v = v
+}
+
+func (b *Box) Get() interface{} {
+	return b.v
+}
+
+type Pair struct {
+	First, Second interface{}
+}
+
+func (p *Pair) Put(first, second interface{}) {
+	p.First = first
+	p.Second = second
+}
+
+func Log(v interface{}) {
+	core.Sink(v)
+}
+
+func Discard(v interface{}) {}
@@ -0,0 +1,68 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"levee_analysistest/crosspkg.com/cache"
+	"levee_analysistest/crosspkg.com/core"
+	// The facts of a package are available to the packages importing it.
+	_ "levee_analysistest/crosspkg.com/producer"
+)
+
+func TestGlobalHoldingSourceOfAnotherPackage() {
+	core.Sink(cache.Last) // want "a source has reached a sink"
+}
+
+func TestGlobalNotHoldingSource() {
+	core.Sink(cache.Count)
+}
+
+func TestMethodStoringArgumentInReceiver(s *core.Source) {
+	b := &cache.Box{}
+	b.Put(s)
+	core.Sink(b) // want "a source has reached a sink"
+}
+
+func TestMethodReturningArgumentStoredInReceiver(s *core.Source) {
+	b := &cache.Box{}
+	b.Put(s)
+	core.Sink(b.Get()) // want "a source has reached a sink"
+}
+
+func TestMethodReturningValueStoredInReceiver(s *core.Source) {
+	b := &cache.Box{}
+	b.Put("ok")
+	core.Sink(b.Get())
+}
+
+func TestFieldStoredByMethodOfAnotherPackage(s *core.Source) {
+	p := &cache.Pair{}
+	p.Put(s, "ok")
+	core.Sink(p.First) // want "a source has reached a sink"
+}
+
+func TestOtherFieldStoredByMethodOfAnotherPackage(s *core.Source) {
+	p := &cache.Pair{}
+	p.Put(s, "ok")
+	core.Sink(p.Second)
+}
+
+func TestFunctionSinkingArgument(s *core.Source) {
+	cache.Log(s) // want "a source has reached a sink"
+}
+
+func TestFunctionNotSinkingArgument(s *core.Source) {
+	cache.Discard(s)
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"levee_analysistest/example/core"
+)
+
+type Holder struct {
+	Value interface{}
+}
+
+func TestBothEngines(s core.Source) {
+	core.Sink(s)
+}
+
+func TestNeitherEngine(s core.Source) {
+	var v interface{} = s
+	v = "not a source"
+	core.Sink(v)
+}
+
+// The EAR engine does not take the order of instructions into account.
+func TestSinkBeforeStore(s core.Source) {
+	h := &Holder{}
+	core.Sink(h) // want "a source has reached a sink, according to the ear engine only"
+	h.Value = s
+}
+
+// The EAR engine only follows the source through EARTaintCallSpan calls.
+func TestSinkInCallee(s core.Source) {
+	sinkThrough(s) // want "a source has reached a sink, according to the propagation engine only"
+}
+
+func sinkThrough(v interface{}) {
+	sinkThroughAgain(v)
+}
+
+func sinkThroughAgain(v interface{}) {
+	core.Sink(v)
+}
+
+// A suppression is used if it suppresses a finding of either engine,
+// whether or not the engines differ.
+func TestSuppressedForBothEngines(s core.Source) {
+	core.Sink(s) // levee.DoNotReport(reason="the sink redacts sources")
+}
+
+func TestSuppressedForOneEngine(s core.Source) {
+	h := &Holder{}
+	core.Sink(h) // levee.DoNotReport(reason="the sink redacts sources")
+	h.Value = s
+}
+
+func TestSuppressedForNeitherEngine() {
+	core.Sink("safe") // levee.DoNotReport(reason="not a source") // want "suppression does not suppress any finding"
+}
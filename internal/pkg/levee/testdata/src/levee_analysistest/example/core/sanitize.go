@@ -25,3 +25,9 @@ func SanitizeSource(s Source) Source {
 func SanitizePtr(s *Source) {
 	s.Data = "<redacted>"
 }
+
+// RedactInto stores a redacted copy of src in dst.
+// Only dst is sanitized in place.
+func RedactInto(dst *Source, src Source) {
+	dst.ID = src.ID
+}
@@ -137,3 +137,10 @@ func TestSuppressNestedCall(s core.Source) {
 		core.SinkAndReturn(s), // levee.DoNotReport // want "a source has reached a sink"
 	)
 }
+
+func TestSuppressDeferredAndGoCalls(s core.Source) {
+	defer core.Sink(s) // levee.DoNotReport
+	// levee.DoNotReport
+	go core.Sink(s)
+	defer core.Sink(s) // want "a source has reached a sink"
+}
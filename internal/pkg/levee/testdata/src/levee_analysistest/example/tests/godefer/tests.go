@@ -0,0 +1,43 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package godefer contains tests for sinks that are called
+// through go and defer statements.
+package godefer
+
+import (
+	"levee_analysistest/example/core"
+)
+
+func TestGoSink(s core.Source) {
+	go core.Sink(s) // want "a source has reached a sink"
+}
+
+func TestDeferSink(s core.Source) {
+	defer core.Sink(s) // want "a source has reached a sink"
+}
+
+func TestDeferSinkAfterSanitization(s core.Source) {
+	sanitized := core.Sanitize(s)[0]
+	defer core.Sink(sanitized)
+}
+
+func TestDeferSinkOfNonSource(s core.Source, i *core.Innocuous) {
+	defer core.Sink(i)
+	go core.Sink(i)
+}
+
+func TestDeferSinkf(s core.Source) {
+	defer core.Sinkf("source: %v", s.Data) // want "a source has reached a sink"
+}
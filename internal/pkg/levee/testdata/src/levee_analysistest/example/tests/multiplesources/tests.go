@@ -0,0 +1,31 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multiplesources
+
+import (
+	"levee_analysistest/example/core"
+)
+
+func TestEverySourceReachingASinkIsReported(first core.Source, second core.Source) {
+	core.Sinkf("%v %v", first, second) // want "a source has reached a sink\n source: .*tests.go:21:45\n source: .*tests.go:21:64$"
+}
+
+func TestSourcesAreReportedInOrderOfPosition(first core.Source, second core.Source) {
+	core.Sinkf("%v %v", second, first) // want "a source has reached a sink\n source: .*tests.go:25:46\n source: .*tests.go:25:65$"
+}
+
+func TestSourcesNotReachingTheSinkAreNotReported(first core.Source, second core.Source) {
+	core.Sinkf("%v", second) // want "a source has reached a sink\n source: .*tests.go:29:69$"
+}
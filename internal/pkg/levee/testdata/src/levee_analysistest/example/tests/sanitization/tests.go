@@ -102,8 +102,7 @@ func TestPointerTaintedInIfButSanitizedBeforeIfExit() {
 		core.SanitizePtr(s)
 		e = s
 	}
-	// TODO(#155) want no report here
-	core.Sink(e) // want "a source has reached a sink"
+	core.Sink(e)
 }
 
 func TestSanitizedBeforeSinkInLoop() {
@@ -135,3 +134,71 @@ func TestSanitizedAfterSink() {
 	core.Sink(s) // want "a source has reached a sink"
 	core.SanitizePtr(&s)
 }
+
+func TestSanitizedInBothBranches(s *core.Source) {
+	if time.Now().Weekday() == time.Monday {
+		core.SanitizePtr(s)
+	} else {
+		core.SanitizePtr(s)
+	}
+	core.Sink(s)
+}
+
+func TestSanitizedInOneBranchOnly(s *core.Source) {
+	if time.Now().Weekday() == time.Monday {
+		core.SanitizePtr(s)
+	} else {
+		core.Sink(s) // want "a source has reached a sink"
+	}
+	core.Sink(s) // want "a source has reached a sink"
+}
+
+func TestSanitizedInEverySwitchCase(s *core.Source) {
+	switch time.Now().Weekday() {
+	case time.Monday:
+		core.SanitizePtr(s)
+	case time.Tuesday, time.Wednesday:
+		core.SanitizePtr(s)
+	default:
+		core.SanitizePtr(s)
+	}
+	core.Sink(s)
+}
+
+func TestSanitizedInSwitchWithoutDefault(s *core.Source) {
+	switch time.Now().Weekday() {
+	case time.Monday:
+		core.SanitizePtr(s)
+	case time.Tuesday:
+		core.SanitizePtr(s)
+	}
+	core.Sink(s) // want "a source has reached a sink"
+}
+
+func TestSanitizedInBranchesInsideLoop(s *core.Source) {
+	for i := 0; i < 3; i++ {
+		if i%2 == 0 {
+			core.SanitizePtr(s)
+		} else {
+			core.SanitizePtr(s)
+		}
+	}
+	core.Sink(s) // want "a source has reached a sink"
+}
+
+func TestArgumentSanitizedInPlace(s core.Source) {
+	var redacted core.Source
+	core.RedactInto(&redacted, s)
+	core.Sink(redacted)
+}
+
+func TestArgumentNotSanitizedInPlace(s core.Source) {
+	var redacted core.Source
+	core.RedactInto(&redacted, s)
+	core.Sink(s) // want "a source has reached a sink"
+}
+
+func TestSanitizedInPlaceAfterSink(s *core.Source) {
+	core.Sink(s) // want "a source has reached a sink"
+	core.RedactInto(s, core.Source{})
+}
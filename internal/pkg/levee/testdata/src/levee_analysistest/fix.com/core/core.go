@@ -0,0 +1,40 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"levee_analysistest/fix.com/sanitizer"
+)
+
+type Source struct {
+	Data string
+	ID   int
+}
+
+type Logger struct{}
+
+func (l *Logger) Sink(args ...interface{}) {}
+
+func Sink(args ...interface{}) {}
+
+func Sinkf(format string, args ...interface{}) {}
+
+func SinkTo(l *Logger, arg interface{}) {}
+
+func SinkSource(s Source) {}
+
+func SinkRedacted(arg interface{}) {
+	Sink(sanitizer.Redact(arg))
+}
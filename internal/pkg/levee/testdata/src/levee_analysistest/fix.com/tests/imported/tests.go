@@ -0,0 +1,56 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imported
+
+import (
+	"levee_analysistest/fix.com/core"
+	"levee_analysistest/fix.com/sanitizer"
+)
+
+func TestFixWrapsTaintedArgument(s core.Source) {
+	core.Sink(s) // want "a source has reached a sink"
+}
+
+func TestFixWrapsOnlyTaintedArguments(s core.Source) {
+	core.Sinkf("%v %v", s.ID, s) // want "a source has reached a sink"
+}
+
+func TestFixWrapsEveryTaintedArgument(s core.Source) {
+	core.Sinkf("%v %v", s, s.Data) // want "a source has reached a sink"
+}
+
+func TestFixDoesNotWrapReceiver(l *core.Logger, s core.Source) {
+	l.Sink(s) // want "a source has reached a sink"
+}
+
+func TestFixWrapsArgumentOfNonVariadicSink(l *core.Logger, s *core.Source) {
+	core.SinkTo(l, s) // want "a source has reached a sink"
+}
+
+func TestFixWrapsArgumentOfGoStatement(s core.Source) {
+	go core.Sink(s) // want "a source has reached a sink"
+}
+
+func TestNoFixIfSanitizerResultIsNotAssignableToParameter(s core.Source) {
+	core.SinkSource(s) // want "a source has reached a sink"
+}
+
+func TestFixWrapsPanicArgument(s core.Source) {
+	panic(s) // want "a source has reached a sink"
+}
+
+func TestSanitizedArgument(s core.Source) {
+	core.Sink(sanitizer.Redact(s))
+}
@@ -0,0 +1,51 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enabled
+
+import (
+	"levee_analysistest/inference.com/core"
+)
+
+// Wrapper is defined from a source type.
+type Wrapper core.Source
+
+// Holder holds a field of a source type.
+type Holder struct {
+	source core.Source
+	Name   string
+}
+
+// Nested is defined from a type that is inferred to be a source type.
+type Nested Holder
+
+func TestTypeDefinedFromSourceTypeIsSource(w Wrapper) {
+	core.Sink(w) // want "a source has reached a sink"
+}
+
+func TestTypeHoldingSourceTypeIsSource(h Holder) {
+	core.Sink(h) // want "a source has reached a sink"
+}
+
+func TestTypeDefinedFromInferredSourceTypeIsSource(n *Nested) {
+	core.Sink(n) // want "a source has reached a sink"
+}
+
+func TestFieldOfInferredSourceTypeThatDoesNotHoldSourceIsNotSource(h Holder) {
+	core.Sink(h.Name)
+}
+
+func TestFieldHoldingSourceTypeIsSource(h Holder) {
+	core.Sink(h.source) // want "a source has reached a sink"
+}
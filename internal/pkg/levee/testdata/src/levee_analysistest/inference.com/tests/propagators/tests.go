@@ -0,0 +1,31 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package propagators
+
+import (
+	"levee_analysistest/inference.com/core"
+)
+
+type Holder struct {
+	source core.Source
+}
+
+func (h Holder) Secret() string {
+	return h.source.Data
+}
+
+func TestFieldPropagatorOfInferredSourceType(h Holder) {
+	core.Sink(h.Secret()) // want "a source has reached a sink"
+}
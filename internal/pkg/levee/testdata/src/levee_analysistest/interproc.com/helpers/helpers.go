@@ -0,0 +1,55 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package helpers holds functions whose parameters reach sinks.
+// Since they do not handle sources themselves, no reports are made within
+// them. Instead, reports are made where they are called with sources.
+package helpers
+
+import (
+	"levee_analysistest/interproc.com/core"
+)
+
+func Log(msg string) {
+	core.Sink(msg)
+}
+
+func LogWithPrefix(prefix, msg string) {
+	Log(prefix + msg)
+}
+
+func LogSanitized(msg string) {
+	core.Sink(core.Sanitize(msg))
+}
+
+func LogLength(msg string) {
+	core.Sink(len(msg))
+}
+
+func Quote(msg string) string {
+	return "'" + msg + "'"
+}
+
+func Collect(dst *[]string, msg string) {
+	*dst = append(*dst, msg)
+}
+
+func LogSource(s core.Source) {
+	core.Sink(s) // want "a source has reached a sink"
+}
+
+func LogSeparately(first, second string) {
+	core.Sink(first)
+	core.Sink(second)
+}
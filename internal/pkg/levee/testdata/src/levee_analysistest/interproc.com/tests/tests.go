@@ -0,0 +1,69 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"levee_analysistest/interproc.com/core"
+	"levee_analysistest/interproc.com/helpers"
+)
+
+func TestSourcePassedToHelperThatReachesSink(s core.Source) {
+	helpers.Log(s.Data) // want "a source has reached a sink\n source: .*tests.go:\\d+:\\d+\n sink: .*helpers.go:25:11$"
+}
+
+func TestSourcePassedToHelperThatReachesSinkTransitively(s core.Source) {
+	helpers.LogWithPrefix("data: ", s.Data) // want "a source has reached a sink\n source: .*\n sink: .*helpers.go:25:11$"
+}
+
+func TestSourcePassedToHelperThatSanitizes(s core.Source) {
+	helpers.LogSanitized(s.Data)
+}
+
+func TestSourcePassedToHelperThatDoesNotSinkIt(s core.Source) {
+	helpers.LogLength(s.Data)
+}
+
+func TestSourceFlowsThroughHelperResult(s core.Source) {
+	core.Sink(helpers.Quote(s.Data)) // want "a source has reached a sink"
+}
+
+func TestSourceFlowsThroughHelperArgument(s core.Source, collected *[]string) {
+	helpers.Collect(collected, s.Data)
+	core.Sink(*collected) // want "a source has reached a sink"
+}
+
+func TestSourcePassedToHelperAfterSanitization(s core.Source) {
+	helpers.Log(core.Sanitize(s.Data))
+}
+
+func TestSourceHandledByHelperIsReportedWithinHelper(s core.Source) {
+	helpers.LogSource(s)
+}
+
+func TestNonSourcePassedToHelper(s core.Source) {
+	helpers.Log("no source here")
+}
+
+func TestHelperDefinedInSamePackage(s core.Source) {
+	logLocally(s.Data) // want "a source has reached a sink\n source: .*\n sink: .*tests.go:\\d+:\\d+$"
+}
+
+func logLocally(msg string) {
+	core.Sink(msg)
+}
+
+func TestSourcesReachDifferentSinksWithinHelper(a, b core.Source) {
+	helpers.LogSeparately(a.Data, b.Data) // want "a source has reached a sink\n source: .*\n source: .*\n sink: .*helpers.go:53:11\n sink: .*helpers.go:54:11$"
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+type Credentials struct {
+	Token string
+}
+
+type User struct {
+	Name  string
+	Email string
+}
+
+// Account holds both credentials and personal information.
+type Account struct {
+	Password string
+	Email    string
+}
+
+// Item is a source that is not explicitly labeled.
+type Item struct {
+	Secret string
+}
+
+// Profile is a source holding another source.
+type Profile struct {
+	Email       string
+	Credentials Credentials
+}
+
+func Log(args ...interface{}) {}
+
+func Analytics(args ...interface{}) {}
+
+func Print(args ...interface{}) {}
+
+// RedactPII removes personal information from its argument, in place.
+func RedactPII(v interface{}) {}
+
+// Scrub removes all sensitive information from its argument, in place.
+func Scrub(v interface{}) {}
@@ -0,0 +1,39 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sanitizers
+
+import (
+	"levee_analysistest/labels.com/core"
+)
+
+func TestSanitizerForLabelSanitizesLabel(u *core.User) {
+	core.RedactPII(u)
+	core.Log(u)
+}
+
+func TestSanitizerForLabelDoesNotSanitizeOtherLabel(c *core.Credentials) {
+	core.RedactPII(c)
+	core.Log(c) // want "label: credentials$"
+}
+
+func TestSanitizerForLabelOnlySanitizesLabel(a *core.Account) {
+	core.RedactPII(a)
+	core.Log(a) // want "label: credentials$"
+}
+
+func TestSanitizerWithoutLabelsSanitizesAllLabels(a *core.Account) {
+	core.Scrub(a)
+	core.Log(a)
+}
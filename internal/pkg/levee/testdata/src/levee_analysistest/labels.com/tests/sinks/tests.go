@@ -0,0 +1,53 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sinks
+
+import (
+	"levee_analysistest/labels.com/core"
+)
+
+func TestLabeledSourceReachesSinkForLabel(c core.Credentials) {
+	core.Analytics(c) // want "a source has reached a sink\n source: .*\n label: credentials$"
+}
+
+func TestLabeledSourceDoesNotReachSinkForOtherLabel(u core.User) {
+	core.Analytics(u)
+}
+
+func TestLabeledSourceReachesSinkForSeveralLabels(u core.User) {
+	core.Log(u) // want "label: pii$"
+}
+
+func TestLabeledSourceReachesSinkForAllLabels(u core.User) {
+	core.Print(u) // want "label: pii$"
+}
+
+func TestSourceWithSeveralLabelsIsReportedForMatchingLabels(a core.Account) {
+	core.Analytics(a) // want "label: credentials$"
+	core.Log(a)       // want "label: credentials, pii$"
+}
+
+func TestUnlabeledSourceDoesNotReachSinkForLabels(i core.Item) {
+	core.Log(i)
+}
+
+func TestUnlabeledSourceIsReportedWithoutLabel(i core.Item) {
+	core.Print(i) // want "^a source has reached a sink\n source: [^\n]*$"
+}
+
+func TestSourceHoldingOtherSourceOnlyHasItsOwnLabels(p core.Profile) {
+	core.Analytics(p)
+	core.Print(p) // want "label: pii$"
+}
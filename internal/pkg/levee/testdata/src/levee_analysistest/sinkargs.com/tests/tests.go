@@ -0,0 +1,46 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"levee_analysistest/sinkargs.com/core"
+)
+
+func TestTaintedContextIsNotReported(ctx core.Context, s core.Source) {
+	ctx["source"] = s
+	core.Log(ctx, "done")
+}
+
+func TestTaintedFormatIsReported(ctx core.Context, s core.Source) {
+	core.Log(ctx, s.Data) // want "a source has reached a sink"
+}
+
+func TestTaintedArgIsReported(ctx core.Context, s core.Source) {
+	core.Log(ctx, "%v", s) // want "a source has reached a sink"
+}
+
+func TestTaintedContextIsNotReportedForMethod(l *core.Logger, ctx core.Context, s core.Source) {
+	ctx["source"] = s
+	l.Infof(ctx, "done")
+}
+
+func TestTaintedArgIsReportedForMethod(l *core.Logger, ctx core.Context, s core.Source) {
+	l.Infof(ctx, "%v", s) // want "a source has reached a sink"
+}
+
+func TestAllArgsAreSensitiveWithoutPositions(ctx core.Context, s core.Source) {
+	ctx["source"] = s
+	core.Sink(ctx) // want "a source has reached a sink"
+}
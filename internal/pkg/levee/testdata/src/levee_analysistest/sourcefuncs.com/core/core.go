@@ -0,0 +1,37 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+func Getenv(key string) string {
+	return ""
+}
+
+func Hostname() string {
+	return ""
+}
+
+type Client struct{}
+
+func (c *Client) Read(path string) (string, error) {
+	return "", nil
+}
+
+type Request struct{}
+
+func (r *Request) FormValue(key string) string {
+	return ""
+}
+
+func Sink(args ...interface{}) {}
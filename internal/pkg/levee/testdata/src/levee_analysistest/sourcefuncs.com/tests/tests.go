@@ -0,0 +1,47 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"levee_analysistest/sourcefuncs.com/core"
+)
+
+func TestSourceFunctionResultIsSource() {
+	password := core.Getenv("DB_PASSWORD")
+	core.Sink(password) // want "a source has reached a sink"
+}
+
+func TestInlinedSourceFunctionResultIsSource() {
+	core.Sink(core.Getenv("DB_PASSWORD")) // want "a source has reached a sink"
+}
+
+func TestSourceFunctionResultPropagates() {
+	dsn := "user:" + core.Getenv("DB_PASSWORD") + "@localhost"
+	core.Sink(dsn) // want "a source has reached a sink"
+}
+
+func TestSourceMethodResultIsSource(r *core.Request) {
+	core.Sink(r.FormValue("password")) // want "a source has reached a sink"
+}
+
+func TestOnlyConfiguredResultIsSource(c *core.Client) {
+	secret, err := c.Read("secret/db")
+	core.Sink(err)
+	core.Sink(secret) // want "a source has reached a sink"
+}
+
+func TestOtherFunctionResultIsNotSource() {
+	core.Sink(core.Hostname())
+}
@@ -0,0 +1,38 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package static
+
+import (
+	"levee_analysistest/summaries.com/encoding"
+)
+
+func TestSummarizedFunctionTaintsReturnValue(s encoding.Source) {
+	encoding.Sink(encoding.Encode(s)) // want "a source has reached a sink"
+}
+
+func TestSummarizedFunctionTaintsOnlySummarizedReturnValue(s encoding.Source) {
+	header, body := encoding.Split(s)
+	encoding.Sink(header)
+	encoding.Sink(body) // want "a source has reached a sink"
+}
+
+func TestSummarizedMethodTaintsReceiver(b *encoding.Buffer, s encoding.Source) {
+	b.Write(s)
+	encoding.Sink(b) // want "a source has reached a sink"
+}
+
+func TestUnsummarizedFunctionDoesNotTaintReturnValue(s encoding.Source) {
+	encoding.Sink(encoding.Checksum(s))
+}
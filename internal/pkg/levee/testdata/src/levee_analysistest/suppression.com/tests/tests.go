@@ -0,0 +1,68 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"levee_analysistest/suppression.com/core"
+)
+
+func TestSuppressionWithReason(c core.Credentials) {
+	core.Sink(c) // levee.DoNotReport(reason="the sink redacts credentials")
+}
+
+func TestSuppressionWithoutReason(c core.Credentials) {
+	core.Sink(c) // levee.DoNotReport // want "a source has reached a sink" "suppression is missing a reason"
+}
+
+func TestMalformedSuppression(c core.Credentials) {
+	core.Sink(c) // levee.DoNotReport(reason) // want "a source has reached a sink" "malformed suppression: expected = after reason"
+}
+
+func TestSuppressionForLabel(c core.Credentials, u core.User) {
+	core.Sink(c) // levee.DoNotReport(reason="the sink redacts credentials", label=credentials)
+	core.Sink(u) // levee.DoNotReport(reason="the sink redacts credentials", label=credentials) // want "a source has reached a sink" "suppression does not suppress any finding"
+}
+
+func TestSuppressionForAllLabels(c core.Credentials, u core.User) {
+	// levee.DoNotReport(reason="the sink redacts everything", label=credentials, label=pii)
+	core.Sink(c, u)
+	// levee.DoNotReport(reason="the sink redacts credentials", label=credentials) // want "suppression does not suppress any finding"
+	core.Sink(c, u) // want "a source has reached a sink"
+}
+
+// levee.DoNotReport(reason="only called with redacted values", scope=function)
+func TestFunctionSuppression(c core.Credentials) {
+	core.Sink(c)
+	func() {
+		core.Sink(c)
+	}()
+}
+
+func TestFunctionSuppressionWithinFunctionLiteral(c core.Credentials) {
+	func() {
+		// levee.DoNotReport(reason="only called with redacted values", scope=function)
+		core.Sink(c)
+	}()
+	core.Sink(c) // want "a source has reached a sink"
+}
+
+func TestUnusedSuppression(c core.Credentials) {
+	core.Sink("safe") // levee.DoNotReport(reason="not safe") // want "suppression does not suppress any finding"
+}
+
+// levee.DoNotReport(reason="not a source", scope=function) // want "suppression does not suppress any finding"
+func TestUnusedFunctionSuppression(c core.Credentials) {
+	core.Sink("safe")
+}
@@ -0,0 +1,40 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"levee_analysistest/typedfix.com/core"
+	"levee_analysistest/typedfix.com/redact"
+)
+
+func TestFixWrapsArgumentOfSanitizerParameterType(s core.Source) {
+	core.Sink(s.Data) // want "a source has reached a sink"
+}
+
+func TestFixWrapsArgumentOfNonVariadicSink(s core.Source) {
+	core.SinkString(s.Data) // want "a source has reached a sink"
+}
+
+func TestNoFixIfArgumentIsNotAssignableToSanitizerParameter(s core.Source) {
+	core.Sink(s) // want "a source has reached a sink"
+}
+
+func TestNoFixIfSomeArgumentIsNotAssignableToSanitizerParameter(s core.Source) {
+	core.Sinkf("%v %v", s, s.Data) // want "a source has reached a sink"
+}
+
+func TestSanitizedArgument(s core.Source) {
+	core.Sink(redact.String(s.Data))
+}
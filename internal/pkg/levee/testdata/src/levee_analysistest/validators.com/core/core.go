@@ -0,0 +1,50 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"errors"
+)
+
+type Credentials struct {
+	Token string
+}
+
+type User struct {
+	Email string
+}
+
+func Sink(args ...interface{}) {}
+
+// IsSafe reports whether its argument may be logged.
+func IsSafe(v interface{}) bool {
+	return false
+}
+
+// Check returns an error if its argument may not be logged.
+func Check(v interface{}) error {
+	return errors.New("unsafe")
+}
+
+// CheckLen returns the length of its argument,
+// and an error if it may not be logged.
+func CheckLen(v interface{}) (int, error) {
+	return 0, errors.New("unsafe")
+}
+
+// IsPublicEmail reports whether the argument contains no personal information.
+func IsPublicEmail(v interface{}) bool {
+	return false
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"levee_analysistest/validators.com/core"
+)
+
+func TestValidatedInTrueBranch(c *core.Credentials) {
+	if core.IsSafe(c) {
+		core.Sink(c)
+	}
+}
+
+func TestNotValidatedInFalseBranch(c *core.Credentials) {
+	if core.IsSafe(c) {
+		return
+	}
+	core.Sink(c) // want "a source has reached a sink"
+}
+
+func TestNotValidatedAfterBranchesJoin(c *core.Credentials) {
+	if core.IsSafe(c) {
+		core.Sink("safe")
+	}
+	core.Sink(c) // want "a source has reached a sink"
+}
+
+func TestValidatedAfterNegatedEarlyReturn(c *core.Credentials) {
+	if !core.IsSafe(c) {
+		return
+	}
+	core.Sink(c)
+}
+
+func TestValidatedWhenErrorIsNil(c *core.Credentials) {
+	if err := core.Check(c); err != nil {
+		core.Sink(c) // want "a source has reached a sink"
+		return
+	}
+	core.Sink(c)
+}
+
+func TestValidatedWhenErrorEqualsNil(c *core.Credentials) {
+	if core.Check(c) == nil {
+		core.Sink(c)
+	}
+}
+
+func TestValidatedWhenLastErrorIsNil(c *core.Credentials) {
+	n, err := core.CheckLen(c)
+	if err != nil {
+		return
+	}
+	core.Sink(c, n)
+}
+
+func TestNotValidatedWhenResultIsIgnored(c *core.Credentials) {
+	core.IsSafe(c)
+	core.Sink(c) // want "a source has reached a sink"
+}
+
+func TestValidatorForLabelValidatesLabel(u *core.User) {
+	if core.IsPublicEmail(u) {
+		core.Sink(u)
+	}
+}
+
+func TestValidatorForLabelDoesNotValidateOtherLabel(c *core.Credentials) {
+	if core.IsPublicEmail(c) {
+		core.Sink(c) // want "a source has reached a sink"
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package levee
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+
+	"github.com/google/go-flow-levee/internal/pkg/earpointer"
+	"github.com/google/go-flow-levee/internal/pkg/source"
+	"github.com/google/go-flow-levee/internal/pkg/utils"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ssa"
+)
+
+// A step is a location along the path from a source to a sink.
+type step struct {
+	pos token.Pos
+	// A short description of how the taint flows through the location.
+	what string
+}
+
+// A trace accumulates the steps from a source to a sink.
+type trace []step
+
+// add adds a step. Steps without a position, and steps at the same position
+// as the previous step, are not useful to a reader, so they are dropped.
+func (t *trace) add(pos token.Pos, what string) {
+	if pos == token.NoPos || len(*t) > 0 && (*t)[len(*t)-1].pos == pos {
+		return
+	}
+	*t = append(*t, step{pos: pos, what: what})
+}
+
+// propagationTrace describes the path of the taint through some nodes,
+// as recorded by the propagation engine, from a source to a sink.
+func propagationTrace(src *source.Source, path []ssa.Node) trace {
+	var t trace
+	t.add(src.Pos(), "source")
+	for i, n := range path {
+		switch i {
+		case 0:
+			// The root of the propagation is the source.
+		case len(path) - 1:
+			t.add(n.Pos(), "sink")
+		default:
+			t.add(n.Pos(), describeNode(n))
+		}
+	}
+	return t
+}
+
+// earTrace describes the path of the taint from a source to a sink,
+// as found by the EAR engine, through the calls between their functions.
+func earTrace(tr *earpointer.SourceSinkTrace) trace {
+	var t trace
+	t.add(tr.Src.Pos(), "source")
+	for _, c := range tr.Callstack {
+		t.add(c.Pos(), "through the call to "+calleeName(c.Common()))
+	}
+	t.add(tr.Sink.Pos(), "sink")
+	return t
+}
+
+// related describes the steps leading from each source to the sink,
+// which is where the diagnostic is reported, as related information.
+func (f finding) related() []analysis.RelatedInformation {
+	var related []analysis.RelatedInformation
+	for _, rs := range f.sources {
+		for i, s := range rs.trace {
+			if i == len(rs.trace)-1 {
+				break
+			}
+			related = append(related, analysis.RelatedInformation{Pos: s.pos, Message: s.what})
+		}
+	}
+	return related
+}
+
+// describeNode describes how the taint flows through a node.
+func describeNode(n ssa.Node) string {
+	switch t := n.(type) {
+	case *ssa.Call:
+		return "through the call to " + calleeName(t.Common())
+	case *ssa.Go:
+		return "through the call to " + calleeName(t.Common())
+	case *ssa.Defer:
+		return "through the call to " + calleeName(t.Common())
+	case *ssa.Store:
+		return "stored"
+	case *ssa.MapUpdate:
+		return "stored in a map"
+	case *ssa.Send:
+		return "sent on a channel"
+	case *ssa.UnOp:
+		switch t.Op {
+		case token.ARROW:
+			return "received from a channel"
+		case token.MUL:
+			return "loaded"
+		}
+	case *ssa.Field:
+		return fmt.Sprintf("field %s accessed", fieldName(t.X.Type(), t.Field))
+	case *ssa.FieldAddr:
+		return fmt.Sprintf("field %s accessed", fieldName(t.X.Type(), t.Field))
+	case *ssa.Index, *ssa.IndexAddr, *ssa.Lookup:
+		return "element accessed"
+	case *ssa.Slice, *ssa.SliceToArrayPointer:
+		return "sliced"
+	case *ssa.MakeInterface:
+		return "converted to an interface"
+	case *ssa.TypeAssert:
+		return "type asserted"
+	case *ssa.Convert, *ssa.ChangeType, *ssa.ChangeInterface:
+		return "converted"
+	case *ssa.BinOp:
+		return fmt.Sprintf("combined with %s", t.Op)
+	case *ssa.Alloc:
+		if _, ok := utils.Dereference(t.Type()).(*types.Array); ok {
+			return "stored in an array"
+		}
+		return "stored in a variable"
+	case *ssa.MakeChan:
+		return "stored in a channel"
+	case *ssa.MakeMap:
+		return "stored in a map"
+	case *ssa.MakeSlice:
+		return "stored in a slice"
+	case *ssa.Extract:
+		return "extracted from a tuple"
+	case *ssa.Parameter:
+		return "passed as parameter " + t.Name()
+	case *ssa.FreeVar:
+		return "captured by a closure"
+	}
+	return "propagated"
+}
+
+// calleeName names the function called by a call. A function value is named
+// after the variable or field holding it, or else described by its signature.
+func calleeName(c *ssa.CallCommon) string {
+	switch {
+	case c.IsInvoke():
+		return c.Method.Name()
+	case c.StaticCallee() != nil:
+		return c.StaticCallee().Name()
+	}
+	switch v := c.Value.(type) {
+	case *ssa.Builtin, *ssa.Parameter, *ssa.FreeVar:
+		return v.Name()
+	case *ssa.Field:
+		return fieldName(v.X.Type(), v.Field)
+	case *ssa.UnOp:
+		switch x := v.X.(type) {
+		case *ssa.Global:
+			return x.Name()
+		case *ssa.FieldAddr:
+			return fieldName(x.X.Type(), x.Field)
+		}
+	}
+	return "a function of type " + c.Signature().String()
+}
+
+func fieldName(t types.Type, field int) string {
+	if st, ok := utils.Dereference(t).Underlying().(*types.Struct); ok {
+		return st.Field(field).Name()
+	}
+	return fmt.Sprint(field)
+}
@@ -0,0 +1,312 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package paramflow implements an interprocedural analysis that determines,
+// for each function, where taint flows from the function's parameters:
+// to its results, to its other parameters, or to a sink within it.
+// Functions are analyzed bottom-up, and their flows are exported as facts
+// so that they are available when analyzing callers in other packages.
+// Recursive functions are analyzed until their flows no longer change.
+// Anonymous functions are not analyzed, since facts cannot be attached to them.
+package paramflow
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/google/go-flow-levee/internal/pkg/callees"
+	"github.com/google/go-flow-levee/internal/pkg/config"
+	"github.com/google/go-flow-levee/internal/pkg/fieldtags"
+	"github.com/google/go-flow-levee/internal/pkg/propagation"
+	"github.com/google/go-flow-levee/internal/pkg/propagation/summary"
+	infer "github.com/google/go-flow-levee/internal/pkg/sourceinfer"
+	"github.com/google/go-flow-levee/internal/pkg/sourcetype"
+	"github.com/google/go-flow-levee/internal/pkg/utils"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/buildssa"
+	"golang.org/x/tools/go/ssa"
+)
+
+// ResultType maps functions to their flows. It holds the functions
+// of the analyzed package as well as those of its dependencies.
+type ResultType = summary.Flows
+
+type funcFlows struct {
+	summary.FuncFlows
+}
+
+func (f funcFlows) AFact() {}
+
+func (f funcFlows) String() string {
+	var descs []string
+	for i, pf := range f.Params {
+		var flows []string
+		if len(pf.TaintedArgs) > 0 {
+			flows = append(flows, fmt.Sprintf("taints args %v", pf.TaintedArgs))
+		}
+		if len(pf.TaintedRets) > 0 {
+			flows = append(flows, fmt.Sprintf("taints results %v", pf.TaintedRets))
+		}
+		if pf.ReachesSink() {
+			flows = append(flows, "reaches a sink")
+		}
+		if len(flows) > 0 {
+			descs = append(descs, fmt.Sprintf("param %d %s", i, strings.Join(flows, " and ")))
+		}
+	}
+	return strings.Join(descs, "; ")
+}
+
+// Analyzer reads its configuration from the file selected by the -config flag.
+var Analyzer = NewAnalyzer(nil, callees.Analyzer, fieldtags.Analyzer, infer.Analyzer)
+
+// NewAnalyzer returns an analyzer bound to conf, which requires the resolvedCallees,
+// taggedFields and inferredSources analyzers bound to the same configuration.
+// If conf is nil, the analyzer reads its configuration as Analyzer does.
+func NewAnalyzer(conf *config.Config, resolvedCallees, taggedFields, inferredSources *analysis.Analyzer) *analysis.Analyzer {
+	return &analysis.Analyzer{
+		Name: "paramflow",
+		Doc: `This analyzer determines where taint flows from function parameters.
+
+Taint from a parameter may flow to the function's results, to its other
+parameters, or to a sink within the function.`,
+		Flags: config.NewFlagSet(conf),
+		Run: func(pass *analysis.Pass) (interface{}, error) {
+			taggedFields := pass.ResultOf[taggedFields].(fieldtags.ResultType)
+			inferred := pass.ResultOf[inferredSources].(infer.ResultType)
+			resolved := pass.ResultOf[resolvedCallees].(callees.ResultType)
+			return run(pass, conf, taggedFields, inferred, resolved)
+		},
+		Requires:   []*analysis.Analyzer{buildssa.Analyzer, resolvedCallees, taggedFields, inferredSources},
+		ResultType: reflect.TypeOf(new(ResultType)).Elem(),
+		FactTypes:  []analysis.Fact{new(funcFlows)},
+	}
+}
+
+func run(pass *analysis.Pass, conf *config.Config, taggedFields fieldtags.ResultType, inferred infer.ResultType, resolved callees.ResultType) (interface{}, error) {
+	ssaInput := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
+
+	conf, err := config.Load(conf, pass.Pkg.Path())
+	if err != nil {
+		return nil, err
+	}
+
+	flows := summary.Flows{}
+	for _, f := range pass.AllObjectFacts() {
+		flows[f.Object] = &f.Fact.(*funcFlows).FuncFlows
+	}
+
+	for _, component := range bottomUp(ssaInput.SrcFuncs) {
+		var fns []*ssa.Function
+		for _, fn := range component {
+			// Anonymous functions have no object to attach a fact to,
+			// so calls to them are handled as calls to functions without flows.
+			if fn.Object() == nil || fn.Pkg != ssaInput.Pkg {
+				continue
+			}
+			// The flows of sinks, sanitizers and excluded functions are irrelevant.
+			path, recv, name := utils.DecomposeFunction(fn)
+			if conf.IsSink(path, recv, name) || conf.IsSanitizer(path, recv, name) || conf.IsExcluded(path, recv, name) {
+				continue
+			}
+			fns = append(fns, fn)
+		}
+		// The flows of recursive functions depend on each other,
+		// so they are analyzed until their flows no longer change.
+		recursive := isRecursive(component)
+		for changed := true; changed; {
+			changed = false
+			for _, fn := range fns {
+				ff := analyze(pass, conf, taggedFields, inferred, flows, resolved, fn)
+				if reflect.DeepEqual(ff, flows[fn.Object()]) {
+					continue
+				}
+				changed = true
+				if ff == nil {
+					delete(flows, fn.Object())
+					continue
+				}
+				flows[fn.Object()] = ff
+			}
+			changed = changed && recursive
+		}
+		for _, fn := range fns {
+			if ff, ok := flows[fn.Object()]; ok {
+				pass.ExportObjectFact(fn.Object(), &funcFlows{*ff})
+			}
+		}
+	}
+
+	return flows, nil
+}
+
+// analyze determines where taint flows from each of a function's parameters.
+// It returns nil if taint does not flow anywhere.
+func analyze(pass *analysis.Pass, conf *config.Config, tf fieldtags.ResultType, inferred infer.ResultType, flows summary.Flows, resolved callees.ResultType, fn *ssa.Function) *summary.FuncFlows {
+	labels := conf.Labels()
+	sinks := propagation.Sinks(fn, conf, flows, resolved)
+	var rets []*ssa.Return
+	for _, b := range fn.Blocks {
+		if ret, ok := b.Instrs[len(b.Instrs)-1].(*ssa.Return); ok {
+			rets = append(rets, ret)
+		}
+	}
+
+	ff := &summary.FuncFlows{Params: make([]summary.ParamFlows, len(fn.Params))}
+	hasFlows := false
+	for i, p := range fn.Params {
+		prop := propagation.Taint(p, labels, conf, tf, flows, resolved)
+		pf := &ff.Params[i]
+
+		for j, other := range fn.Params {
+			if j != i && prop.IsTaintedValue(other) {
+				pf.TaintedArgs = append(pf.TaintedArgs, j)
+			}
+		}
+
+		for j := 0; j < fn.Signature.Results().Len(); j++ {
+			for _, ret := range rets {
+				if prop.IsTaintedArg(ret, ret.Results[j]) {
+					pf.TaintedRets = append(pf.TaintedRets, j)
+					break
+				}
+			}
+		}
+
+		// A parameter that is itself a source is reported within the function,
+		// so its flows to sinks need not be reported at call sites.
+		if !sourcetype.IsSourceType(conf, tf, inferred, p.Type()) {
+			for _, s := range sinks {
+				sinkLabels, inner := prop.ReachingLabels(s)
+				if len(sinkLabels) == 0 {
+					continue
+				}
+				for _, l := range sinkLabels {
+					pf.SinkLabels = addLabel(pf.SinkLabels, l)
+				}
+				if !pf.Sink.IsValid() {
+					pf.Sink = inner
+					if !inner.IsValid() {
+						pf.Sink = pass.Fset.Position(s.Instr.Pos())
+					}
+				}
+			}
+		}
+
+		hasFlows = hasFlows || len(pf.TaintedArgs) > 0 || len(pf.TaintedRets) > 0 || pf.ReachesSink()
+	}
+
+	if !hasFlows {
+		return nil
+	}
+	return ff
+}
+
+// bottomUp returns the strongly connected components of the static call graph
+// of functions, such that callees come before their callers. Functions in
+// the same component, i.e. in the same cycle, may call each other.
+func bottomUp(fns []*ssa.Function) [][]*ssa.Function {
+	inPkg := map[*ssa.Function]bool{}
+	for _, fn := range fns {
+		inPkg[fn] = true
+	}
+
+	// Tarjan's algorithm produces the components in reverse topological order.
+	var (
+		components [][]*ssa.Function
+		stack      []*ssa.Function
+		onStack    = map[*ssa.Function]bool{}
+		index      = map[*ssa.Function]int{}
+		lowlink    = map[*ssa.Function]int{}
+	)
+	var visit func(fn *ssa.Function)
+	visit = func(fn *ssa.Function) {
+		index[fn] = len(index)
+		lowlink[fn] = index[fn]
+		stack = append(stack, fn)
+		onStack[fn] = true
+		for _, callee := range staticCallees(fn, inPkg) {
+			if _, ok := index[callee]; !ok {
+				visit(callee)
+				if lowlink[callee] < lowlink[fn] {
+					lowlink[fn] = lowlink[callee]
+				}
+			} else if onStack[callee] && index[callee] < lowlink[fn] {
+				lowlink[fn] = index[callee]
+			}
+		}
+		if lowlink[fn] != index[fn] {
+			return
+		}
+		var component []*ssa.Function
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == fn {
+				break
+			}
+		}
+		components = append(components, component)
+	}
+	for _, fn := range fns {
+		if _, ok := index[fn]; !ok {
+			visit(fn)
+		}
+	}
+	return components
+}
+
+// staticCallees returns the functions among fns that are statically called by fn.
+func staticCallees(fn *ssa.Function, fns map[*ssa.Function]bool) []*ssa.Function {
+	var result []*ssa.Function
+	for _, b := range fn.Blocks {
+		for _, instr := range b.Instrs {
+			call, ok := instr.(ssa.CallInstruction)
+			if !ok {
+				continue
+			}
+			if callee := call.Common().StaticCallee(); callee != nil && fns[callee] {
+				result = append(result, callee)
+			}
+		}
+	}
+	return result
+}
+
+// isRecursive determines whether the functions of a component call each other,
+// i.e. whether the component has several functions or a function calling itself.
+func isRecursive(component []*ssa.Function) bool {
+	if len(component) > 1 {
+		return true
+	}
+	fn := component[0]
+	for _, callee := range staticCallees(fn, map[*ssa.Function]bool{fn: true}) {
+		if callee == fn {
+			return true
+		}
+	}
+	return false
+}
+
+func addLabel(labels []string, label string) []string {
+	for _, l := range labels {
+		if l == label {
+			return labels
+		}
+	}
+	return append(labels, label)
+}
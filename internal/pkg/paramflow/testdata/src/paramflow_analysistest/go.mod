@@ -0,0 +1,3 @@
+module paramflow_analysistest
+
+go 1.15
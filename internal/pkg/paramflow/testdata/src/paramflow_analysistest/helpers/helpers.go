@@ -0,0 +1,80 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helpers
+
+import (
+	"paramflow_analysistest/core"
+)
+
+func Log(msg string) { // want Log:"param 0 reaches a sink"
+	core.Sink(msg)
+}
+
+func LogSecond(prefix, msg string) { // want LogSecond:"param 1 reaches a sink"
+	Log(msg)
+}
+
+func LogSanitized(msg string) {
+	core.Sink(core.Sanitize(msg))
+}
+
+func LogSource(s core.Source) {
+	core.Sink(s)
+}
+
+func Quote(msg string) string { // want Quote:"param 0 taints results \\[0\\]"
+	return "'" + msg + "'"
+}
+
+func Swap(a, b string) (string, string) { // want Swap:"param 0 taints results \\[1\\]; param 1 taints results \\[0\\]"
+	return b, a
+}
+
+func Collect(dst *[]string, msg string) { // want Collect:"param 1 taints args \\[0\\]"
+	*dst = append(*dst, msg)
+}
+
+func QuoteAndLog(msg string) string { // want QuoteAndLog:"param 0 taints results \\[0\\] and reaches a sink"
+	q := Quote(msg)
+	Log(q)
+	return q
+}
+
+func Length(msg string) int {
+	return len(msg)
+}
+
+func LogEven(msg string, n int) { // want LogEven:"param 0 reaches a sink"
+	if n == 0 {
+		Log(msg)
+		return
+	}
+	LogOdd(msg, n-1)
+}
+
+func LogOdd(msg string, n int) { // want LogOdd:"param 0 reaches a sink"
+	if n == 0 {
+		return
+	}
+	LogEven(msg, n-1)
+}
+
+func LogLast(first, rest string, n int) { // want LogLast:"param 0 reaches a sink; param 1 reaches a sink"
+	if n == 0 {
+		Log(first)
+		return
+	}
+	LogLast(rest, rest, n-1)
+}
@@ -21,8 +21,10 @@ import (
 	"go/types"
 	"log"
 
+	"github.com/google/go-flow-levee/internal/pkg/callees"
 	"github.com/google/go-flow-levee/internal/pkg/config"
 	"github.com/google/go-flow-levee/internal/pkg/fieldtags"
+	"github.com/google/go-flow-levee/internal/pkg/propagation/summary"
 	"github.com/google/go-flow-levee/internal/pkg/sanitizer"
 	"github.com/google/go-flow-levee/internal/pkg/utils"
 	"golang.org/x/tools/go/pointer"
@@ -35,24 +37,49 @@ type Propagation struct {
 	root         ssa.Node
 	tainted      map[ssa.Node]bool
 	preOrder     []ssa.Node
-	sanitizers   []*sanitizer.Sanitizer
+	sanitizers   []labeledSanitizer
 	config       *config.Config
 	taggedFields fieldtags.ResultType
+	// labels are the labels of the root. Every tainted node carries
+	// these labels, except for those sanitized before the node is reached.
+	labels []string
+	// flows are the interprocedural flows of the functions that may be called.
+	flows summary.Flows
+	// callees resolves the functions that may be called at call sites.
+	callees callees.ResultType
+	// predecessors maps each tainted node other than the root
+	// to the node from which it was tainted.
+	predecessors map[ssa.Node]ssa.Node
+	// visiting is the node whose neighbors are being tainted.
+	visiting ssa.Node
+}
+
+// A labeledSanitizer is a sanitizer or validator call along with the labels it sanitizes.
+type labeledSanitizer struct {
+	sanitizer.Dominator
+	labels []string
 }
 
 // Taint performs a depth-first search of the graph formed by SSA Referrers and
-// Operands relationships, beginning at the given root node.
-func Taint(n ssa.Node, conf *config.Config, taggedFields fieldtags.ResultType) Propagation {
+// Operands relationships, beginning at the given root node, which is tainted
+// with the given labels. Taint propagates through calls to functions whose
+// flows are known, and stops at calls that may only call sanitizers.
+func Taint(n ssa.Node, labels []string, conf *config.Config, taggedFields fieldtags.ResultType, flows summary.Flows, resolved callees.ResultType) Propagation {
 	prop := Propagation{
 		root:         n,
+		labels:       labels,
 		tainted:      make(map[ssa.Node]bool),
+		predecessors: make(map[ssa.Node]ssa.Node),
 		config:       conf,
 		taggedFields: taggedFields,
+		flows:        flows,
+		callees:      resolved,
 	}
 	maxInstrReached := map[*ssa.BasicBlock]int{}
 
 	prop.taint(n, maxInstrReached, nil, false)
 	// ensure immediate referrers are visited
+	prop.visiting = n
 	prop.taintReferrers(n, maxInstrReached, nil)
 
 	return prop
@@ -74,6 +101,9 @@ func (prop *Propagation) taint(n ssa.Node, maxInstrReached map[*ssa.BasicBlock]i
 	}
 	prop.preOrder = append(prop.preOrder, n)
 	prop.tainted[n] = true
+	if prop.visiting != nil {
+		prop.predecessors[n] = prop.visiting
+	}
 
 	mirCopy := map[*ssa.BasicBlock]int{}
 	for m, i := range maxInstrReached {
@@ -93,7 +123,10 @@ func (prop *Propagation) taint(n ssa.Node, maxInstrReached map[*ssa.BasicBlock]i
 		lastBlockVisited = instr.Block()
 	}
 
+	previous := prop.visiting
+	prop.visiting = n
 	prop.taintNeighbors(n, mirCopy, lastBlockVisited)
+	prop.visiting = previous
 }
 
 func (prop *Propagation) shouldNotTaint(n ssa.Node, maxInstrReached map[*ssa.BasicBlock]int, lastBlockVisited *ssa.BasicBlock, isReferrer bool) bool {
@@ -241,9 +274,32 @@ func (prop *Propagation) taintOperands(n ssa.Node, maxInstrReached map[*ssa.Basi
 }
 
 func (prop *Propagation) taintCall(call *ssa.Call, maxInstrReached map[*ssa.BasicBlock]int, lastBlockVisited *ssa.BasicBlock) {
-	if callee := call.Call.StaticCallee(); callee != nil && prop.config.IsSanitizer(utils.DecomposeFunction(callee)) {
-		prop.sanitizers = append(prop.sanitizers, &sanitizer.Sanitizer{Call: call})
-		return
+	if sanitized := prop.calleeLabels(call, prop.config.IsSanitizerForLabel); len(sanitized) > 0 {
+		if prop.cleansFollowingUses(call) {
+			prop.sanitizers = append(prop.sanitizers, labeledSanitizer{
+				Dominator: sanitizer.Sanitizer{Call: call},
+				labels:    sanitized,
+			})
+		}
+		// Taint only stops at a sanitizer if it sanitizes all of the root's labels.
+		if len(sanitized) == len(prop.labels) {
+			return
+		}
+	}
+
+	if validated := prop.calleeLabels(call, prop.config.IsValidatorForLabel); len(validated) > 0 {
+		// A validator only sanitizes its arguments where its check succeeded.
+		// If its result does not guard any block, nothing is sanitized.
+		if v, ok := sanitizer.NewValidator(call); ok {
+			prop.sanitizers = append(prop.sanitizers, labeledSanitizer{
+				Dominator: v,
+				labels:    validated,
+			})
+		}
+		// A validator's result does not carry the taint of its arguments.
+		if len(validated) == len(prop.labels) {
+			return
+		}
 	}
 
 	// Some builtins require special handling
@@ -338,21 +394,130 @@ func (prop *Propagation) canReach(start *ssa.BasicBlock, dest *ssa.BasicBlock) b
 	return false
 }
 
+// calleeLabels returns the root's labels for which a call is a sanitizer
+// or a validator, as determined by the match function.
+// A label is only returned if every function that may be called
+// matches it.
+func (prop *Propagation) calleeLabels(call *ssa.Call, match func(path, recv, name, label string) bool) []string {
+	fns := prop.callees.Callees(call)
+	if len(fns) == 0 {
+		return nil
+	}
+	var labels []string
+	for _, l := range prop.labels {
+		sanitizes := true
+		for _, fn := range fns {
+			path, recv, name, _ := decomposeCallee(fn)
+			if !match(path, recv, name, l) {
+				sanitizes = false
+				break
+			}
+		}
+		if sanitizes {
+			labels = append(labels, l)
+		}
+	}
+	return labels
+}
+
+// cleansFollowingUses determines whether a call to a sanitizer cleans the uses
+// of the tainted values that follow it. This is the case unless the taint
+// reached the call through an argument that is not sanitized in place
+// by every function that may be called.
+func (prop *Propagation) cleansFollowingUses(call *ssa.Call) bool {
+	from := prop.predecessors[call]
+	for pos, arg := range utils.CallArgs(call.Common()) {
+		if arg.(ssa.Node) != from {
+			continue
+		}
+		for _, fn := range prop.callees.Callees(call) {
+			path, recv, name, bound := decomposeCallee(fn)
+			fnPos := pos
+			if bound {
+				// The receiver is not among the call's arguments.
+				fnPos++
+			}
+			if !prop.config.IsInPlaceSanitizerArg(path, recv, name, fnPos) {
+				return false
+			}
+		}
+		return true
+	}
+	return true
+}
+
 // IsTainted determines whether an instruction is tainted by the Propagation.
 func (prop Propagation) IsTainted(instr ssa.Instruction) bool {
-	return prop.tainted[instr.(ssa.Node)] && !prop.isSanitizedAt(instr)
+	return len(prop.TaintedLabels(instr)) > 0
+}
+
+// TaintedLabels returns the labels with which an instruction is tainted
+// by the Propagation, i.e. the root's labels that are not sanitized
+// when the taint reaches the instruction.
+func (prop Propagation) TaintedLabels(instr ssa.Instruction) []string {
+	if !prop.tainted[instr.(ssa.Node)] {
+		return nil
+	}
+	var labels []string
+	for _, l := range prop.labels {
+		if !prop.isSanitizedAt(instr, l) {
+			labels = append(labels, l)
+		}
+	}
+	return labels
+}
+
+// IsTaintedArg determines whether an argument of an instruction is tainted
+// by the Propagation when it reaches the instruction.
+func (prop Propagation) IsTaintedArg(instr ssa.Instruction, arg ssa.Value) bool {
+	return prop.IsTainted(instr) && prop.tainted[arg.(ssa.Node)]
+}
+
+// IsTaintedValue determines whether a value is reached by the Propagation
+// at any point, without regard to sanitization.
+func (prop Propagation) IsTaintedValue(v ssa.Value) bool {
+	return prop.tainted[v.(ssa.Node)]
+}
+
+// Path returns the nodes through which taint propagates from the Propagation's
+// root to a node, starting with the root and ending with the node.
+// If the node is not tainted, Path returns nil.
+func (prop Propagation) Path(n ssa.Node) []ssa.Node {
+	if !prop.tainted[n] {
+		return nil
+	}
+	var path []ssa.Node
+	for ; n != nil; n = prop.predecessors[n] {
+		path = append(path, n)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
 }
 
 // isSanitizedAt determines whether the taint propagated from the Propagation's root
-// is sanitized when it reaches the target instruction.
-func (prop Propagation) isSanitizedAt(instr ssa.Instruction) bool {
+// is sanitized for the given label when it reaches the target instruction.
+// The taint is sanitized if a single sanitizer dominates the instruction,
+// or if every path from the root to the instruction goes through some sanitizer,
+// e.g. when the taint is sanitized separately in each branch of an if statement.
+func (prop Propagation) isSanitizedAt(instr ssa.Instruction, label string) bool {
+	var sanitizers []sanitizer.Dominator
 	for _, san := range prop.sanitizers {
+		if !hasLabel(san.labels, label) {
+			continue
+		}
 		if san.Dominates(instr) {
 			return true
 		}
+		sanitizers = append(sanitizers, san.Dominator)
+	}
+	if len(sanitizers) == 0 {
+		return false
 	}
 
-	return false
+	from, _ := prop.root.(ssa.Instruction)
+	return sanitizer.Covers(sanitizers, from, instr)
 }
 
 type stack []*ssa.BasicBlock
@@ -0,0 +1,218 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package propagation
+
+import (
+	"go/token"
+	"go/types"
+
+	"github.com/google/go-flow-levee/internal/pkg/callees"
+	"github.com/google/go-flow-levee/internal/pkg/config"
+	"github.com/google/go-flow-levee/internal/pkg/propagation/summary"
+	"github.com/google/go-flow-levee/internal/pkg/utils"
+	"golang.org/x/tools/go/ssa"
+)
+
+// A Sink is an instruction that tainted values must not reach.
+type Sink struct {
+	Instr ssa.Instruction
+	// Args are the values that flow into the sink.
+	Args []ssa.Value
+	// isSinkArg determines whether the argument at a given position
+	// is sensitive for a given label.
+	isSinkArg func(pos int, label string) bool
+	// flows are the flows of the called function, if the sink is a call to
+	// a function whose parameters reach sinks.
+	flows *summary.FuncFlows
+}
+
+// Sinks returns the sinks in a function. These are calls, including those
+// made through go and defer statements, that may call sinks
+// or functions whose parameters reach sinks, and, unless they are allowed by
+// the configuration, panics.
+func Sinks(fn *ssa.Function, conf *config.Config, flows summary.Flows, resolved callees.ResultType) []Sink {
+	var sinks []Sink
+	for _, b := range fn.Blocks {
+		for _, instr := range b.Instrs {
+			switch v := instr.(type) {
+			case *ssa.Panic:
+				if !conf.AllowPanicOnTaintedValues {
+					sinks = append(sinks, panicSink(instr, v.X))
+				}
+			// Calls made through go and defer statements are sinks as well.
+			case ssa.CallInstruction:
+				// panic can only be called through go and defer statements,
+				// since direct calls are represented by Panic instructions.
+				if b, ok := v.Common().Value.(*ssa.Builtin); ok && b.Name() == "panic" {
+					if !conf.AllowPanicOnTaintedValues {
+						sinks = append(sinks, panicSink(instr, v.Common().Args[0]))
+					}
+					continue
+				}
+				if s, ok := callSink(v, conf, flows, resolved); ok {
+					sinks = append(sinks, s)
+				}
+			}
+		}
+	}
+	return sinks
+}
+
+// panicSink returns a sink for a panic with the given value.
+// panic is a sink for all labels.
+func panicSink(instr ssa.Instruction, x ssa.Value) Sink {
+	return Sink{
+		Instr:     instr,
+		Args:      []ssa.Value{x},
+		isSinkArg: func(int, string) bool { return true },
+	}
+}
+
+// callSink determines whether a call is a sink. A call is a sink if one of
+// the functions that it may call is a sink. Otherwise, it is a sink if one
+// of these functions has a parameter that reaches a sink.
+func callSink(call ssa.CallInstruction, conf *config.Config, flows summary.Flows, resolved callees.ResultType) (Sink, bool) {
+	fns := resolved.Callees(call)
+	args := utils.CallArgs(call.Common())
+
+	var sinkFns []*ssa.Function
+	for _, fn := range fns {
+		path, recv, name, _ := decomposeCallee(fn)
+		if conf.IsSink(path, recv, name) {
+			sinkFns = append(sinkFns, fn)
+		}
+	}
+	if len(sinkFns) > 0 {
+		return Sink{
+			Instr: call,
+			Args:  args,
+			isSinkArg: func(pos int, label string) bool {
+				for _, fn := range sinkFns {
+					path, recv, name, bound := decomposeCallee(fn)
+					fnPos := pos
+					if bound {
+						// The receiver is not among the call's arguments.
+						fnPos++
+					}
+					if conf.IsSinkArg(path, recv, name, fnPos, label) {
+						return true
+					}
+				}
+				return false
+			},
+		}, true
+	}
+
+	for _, fn := range fns {
+		if fn.Object() == nil {
+			continue
+		}
+		ff := flows[fn.Object()]
+		if ff == nil {
+			continue
+		}
+		for _, pf := range ff.Params {
+			if pf.ReachesSink() {
+				return Sink{
+					Instr: call,
+					Args:  args,
+					isSinkArg: func(pos int, label string) bool {
+						return pos < len(ff.Params) && hasLabel(ff.Params[pos].SinkLabels, label)
+					},
+					flows: ff,
+				}, true
+			}
+		}
+	}
+	return Sink{}, false
+}
+
+// decomposeCallee returns the path, receiver, and name strings of a function
+// that may be called. Synthetic wrappers, such as those that are called
+// through interfaces or method values, stand for the method that they wrap.
+// A bound method wrapper, which is called through a method value, holds
+// the receiver in its only free variable rather than in its parameters.
+// In this case, bound is true.
+func decomposeCallee(fn *ssa.Function) (path, recv, name string, bound bool) {
+	obj, ok := fn.Object().(*types.Func)
+	if !ok || fn.Synthetic == "" {
+		path, recv, name = utils.DecomposeFunction(fn)
+		return path, recv, name, false
+	}
+	if obj.Pkg() != nil {
+		path = obj.Pkg().Path()
+	}
+	if recvVar := obj.Type().(*types.Signature).Recv(); recvVar != nil {
+		recv = utils.UnqualifiedName(recvVar)
+	}
+	bound = fn.Signature.Recv() == nil && len(fn.FreeVars) == 1
+	return path, recv, obj.Name(), bound
+}
+
+// ReachingLabels returns the labels with which the Propagation's taint
+// reaches a sink through one of the sink's sensitive arguments.
+// If the sink is a call to a function whose parameters reach sinks,
+// the position of the sink reached within that function is also returned.
+func (prop Propagation) ReachingLabels(s Sink) ([]string, token.Position) {
+	var (
+		labels []string
+		inner  token.Position
+	)
+	for _, l := range prop.TaintedLabels(s.Instr) {
+		for i, a := range s.Args {
+			if s.isSinkArg(i, l) && prop.IsTaintedArg(s.Instr, a) {
+				labels = append(labels, l)
+				if s.flows != nil && !inner.IsValid() {
+					inner = s.flows.Params[i].Sink
+				}
+				break
+			}
+		}
+	}
+	return labels, inner
+}
+
+// PathToSink returns the nodes through which taint propagates from the
+// Propagation's root to a sensitive argument of a sink for a given label,
+// followed by the sink's instruction. If no such argument is tainted,
+// PathToSink returns nil.
+func (prop Propagation) PathToSink(s Sink, label string) []ssa.Node {
+	positions := prop.TaintedSinkArgs(s, label)
+	if len(positions) == 0 {
+		return nil
+	}
+	return append(prop.Path(s.Args[positions[0]].(ssa.Node)), s.Instr.(ssa.Node))
+}
+
+// TaintedSinkArgs returns the positions of the sensitive arguments of a sink
+// for a given label that are tainted by the Propagation when they reach the sink.
+func (prop Propagation) TaintedSinkArgs(s Sink, label string) []int {
+	var positions []int
+	for i, a := range s.Args {
+		if s.isSinkArg(i, label) && prop.IsTaintedArg(s.Instr, a) {
+			positions = append(positions, i)
+		}
+	}
+	return positions
+}
+
+func hasLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
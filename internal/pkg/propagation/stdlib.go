@@ -16,25 +16,32 @@ package propagation
 
 import (
 	"github.com/google/go-flow-levee/internal/pkg/propagation/summary"
+	"github.com/google/go-flow-levee/internal/pkg/utils"
 	"golang.org/x/tools/go/ssa"
 )
 
 // taintStdlibCall propagates taint through a static call to a standard
-// library function, or through an implementation of a standard library
-// interface function, provided that the function's taint propagation behavior
-// is known (i.e. the function has a summary).
+// library function, through an implementation of a standard library
+// interface function, through a call to a function summarized in the
+// configuration, or through a static call to a function whose flows were
+// determined interprocedurally, provided that the function's taint
+// propagation behavior is known (i.e. the function has a summary).
 func (prop *Propagation) taintStdlibCall(callInstr ssa.CallInstruction, maxInstrReached map[*ssa.BasicBlock]int, lastBlockVisited *ssa.BasicBlock) {
-	summ := summary.For(callInstr)
-	if summ == nil {
+	if summ := summary.For(prop.config, callInstr); summ != nil {
+		prop.applySummary(callInstr, summ, maxInstrReached, lastBlockVisited)
 		return
 	}
-
-	var args []ssa.Value
-	// For "invoke" calls, Value is the receiver
-	if callInstr.Common().IsInvoke() {
-		args = append(args, callInstr.Common().Value)
+	if ff := prop.flows.For(callInstr); ff != nil {
+		for _, summ := range ff.Summaries() {
+			summ := summ
+			prop.applySummary(callInstr, &summ, maxInstrReached, lastBlockVisited)
+		}
 	}
-	args = append(args, callInstr.Common().Args...)
+}
+
+// applySummary propagates taint through a call according to a summary.
+func (prop *Propagation) applySummary(callInstr ssa.CallInstruction, summ *summary.Summary, maxInstrReached map[*ssa.BasicBlock]int, lastBlockVisited *ssa.BasicBlock) {
+	args := utils.CallArgs(callInstr.Common())
 
 	// Determine whether we need to propagate taint.
 	tainted := int64(0)
@@ -48,7 +55,12 @@ func (prop *Propagation) taintStdlibCall(callInstr ssa.CallInstruction, maxInstr
 	}
 
 	// Taint call arguments.
+	// Configured summaries may refer to positions that are out of range
+	// for a given call, so these are skipped.
 	for _, i := range summ.TaintedArgs {
+		if i >= len(args) {
+			continue
+		}
 		prop.taint(args[i].(ssa.Node), maxInstrReached, lastBlockVisited, false)
 	}
 
@@ -83,7 +95,10 @@ func (prop *Propagation) taintStdlibCall(callInstr ssa.CallInstruction, maxInstr
 		e := r.(*ssa.Extract)
 		indexToExtract[e.Index] = e
 	}
-	for i := range summ.TaintedRets {
-		prop.taint(indexToExtract[i], maxInstrReached, lastBlockVisited, true)
+	for _, i := range summ.TaintedRets {
+		// The returned value may not be used.
+		if e, ok := indexToExtract[i]; ok {
+			prop.taint(e, maxInstrReached, lastBlockVisited, true)
+		}
 	}
 }
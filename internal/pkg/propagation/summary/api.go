@@ -13,7 +13,8 @@
 // limitations under the License.
 
 // Package summary provides function summaries for a range of standard
-// library functions that could be involved in a taint propagation.
+// library functions that could be involved in a taint propagation,
+// as well as for functions summarized in the configuration.
 // Function summaries describe the taint-propagation behavior of a given
 // function, e.g. "if these arguments are tainted, then the following
 // arguments/return values should also be tainted".
@@ -23,13 +24,21 @@ import (
 	"go/types"
 	"strings"
 
+	"github.com/google/go-flow-levee/internal/pkg/config"
 	"github.com/google/go-flow-levee/internal/pkg/utils"
 	"golang.org/x/tools/go/ssa"
 )
 
 // For returns the summary for a given call if it exists,
 // or nil if no summary matches the called function.
-func For(call ssa.CallInstruction) *Summary {
+// Summaries provided via configuration take precedence over
+// the built-in ones.
+func For(conf *config.Config, call ssa.CallInstruction) *Summary {
+	if path, recv, name, ok := utils.DecomposeCallee(call.Common()); ok {
+		if fs := conf.FindSummary(path, recv, name); fs != nil {
+			return fromConfig(fs)
+		}
+	}
 	if summ, ok := FuncSummaries[staticFuncName(call)]; ok {
 		return &summ
 	}
@@ -70,6 +79,18 @@ type Summary struct {
 	TaintedRets []int
 }
 
+// fromConfig converts a configured summary to a Summary.
+func fromConfig(fs *config.FuncSummary) *Summary {
+	summ := &Summary{
+		TaintedArgs: fs.TaintedArgs,
+		TaintedRets: fs.TaintedRets,
+	}
+	for _, i := range fs.IfTainted {
+		summ.IfTainted |= 1 << i
+	}
+	return summ
+}
+
 func staticFuncName(call ssa.CallInstruction) string {
 	if sc := call.Common().StaticCallee(); sc != nil {
 		return sc.RelString(call.Parent().Pkg.Pkg)
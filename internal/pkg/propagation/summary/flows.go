@@ -0,0 +1,77 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package summary
+
+import (
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// FuncFlows describes how taint flows from the parameters of a function,
+// as determined by analyzing the function's body.
+// Note that when it's present, the receiver counts as a parameter.
+type FuncFlows struct {
+	// Params holds the flows from each of the function's parameters.
+	Params []ParamFlows
+}
+
+// ParamFlows describes where taint flows from a single parameter.
+type ParamFlows struct {
+	// the positions of the other parameters that become tainted
+	TaintedArgs []int
+	// the positions of the return values that become tainted
+	TaintedRets []int
+	// the labels with which taint reaches a sink within the function,
+	// possibly through further calls
+	SinkLabels []string
+	// the position of the sink that is reached, if SinkLabels is not empty
+	Sink token.Position
+}
+
+// ReachesSink determines whether taint flows from the parameter to a sink.
+func (pf ParamFlows) ReachesSink() bool {
+	return len(pf.SinkLabels) > 0
+}
+
+// Flows maps functions to their FuncFlows.
+type Flows map[types.Object]*FuncFlows
+
+// For returns the flows of the static callee of a call,
+// or nil if the callee's flows are not known.
+func (f Flows) For(call ssa.CallInstruction) *FuncFlows {
+	callee := call.Common().StaticCallee()
+	if callee == nil || callee.Object() == nil {
+		return nil
+	}
+	return f[callee.Object()]
+}
+
+// Summaries converts the flows to one Summary per parameter.
+func (ff *FuncFlows) Summaries() []Summary {
+	var summs []Summary
+	for i, pf := range ff.Params {
+		if i >= 64 || (len(pf.TaintedArgs) == 0 && len(pf.TaintedRets) == 0) {
+			continue
+		}
+		summs = append(summs, Summary{
+			IfTainted:   1 << i,
+			TaintedArgs: pf.TaintedArgs,
+			TaintedRets: pf.TaintedRets,
+		})
+	}
+	return summs
+}
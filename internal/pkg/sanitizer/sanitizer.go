@@ -22,6 +22,12 @@ import (
 	"golang.org/x/tools/go/ssa"
 )
 
+// A Dominator determines whether an instruction can only be executed
+// after the taint has been removed.
+type Dominator interface {
+	Dominates(target ssa.Instruction) bool
+}
+
 // Sanitizer removes the taint.
 type Sanitizer struct {
 	// Call is the underlying call that performs sanitization
@@ -55,3 +61,87 @@ func (s Sanitizer) Dominates(target ssa.Instruction) bool {
 
 	return s.Call.Block().Dominates(target.Block())
 }
+
+// Covers determines whether every path from instruction "from" to instruction
+// "target" goes through one of the sanitizers, i.e. whether the sanitizers
+// collectively dominate the target. This holds when a value is sanitized
+// separately on each branch leading to the target, even though none of the
+// sanitizers dominates the target on its own.
+// If "from" is nil or belongs to another function, paths start at the entry
+// of the target's function.
+func Covers(sanitizers []Dominator, from, target ssa.Instruction) bool {
+	fn := target.Parent()
+	if fn == nil || len(fn.Blocks) == 0 {
+		return false
+	}
+	calls := map[ssa.Instruction]bool{}
+	edges := map[edge]bool{}
+	for _, s := range sanitizers {
+		switch s := s.(type) {
+		case Sanitizer:
+			calls[s.Call] = true
+		case Validator:
+			for _, e := range s.edges {
+				edges[e] = true
+			}
+		}
+	}
+
+	// Scan the instructions of a block from a given index, returning whether
+	// the target is reached and whether a sanitizer is reached first.
+	scan := func(b *ssa.BasicBlock, start int) (reached, blocked bool) {
+		for _, instr := range b.Instrs[start:] {
+			switch {
+			case instr == target:
+				return true, false
+			case calls[instr]:
+				return false, true
+			}
+		}
+		return false, false
+	}
+
+	start, index := fn.Blocks[0], 0
+	if from != nil && from.Parent() == fn {
+		start = from.Block()
+		for i, instr := range start.Instrs {
+			if instr == from {
+				index = i + 1
+				break
+			}
+		}
+	}
+	if reached, blocked := scan(start, index); reached || blocked {
+		return !reached
+	}
+	visited := map[*ssa.BasicBlock]bool{}
+	queue := successors(start, edges)
+	for len(queue) > 0 {
+		b := queue[0]
+		queue = queue[1:]
+		if visited[b] {
+			continue
+		}
+		visited[b] = true
+		reached, blocked := scan(b, 0)
+		if reached {
+			return false
+		}
+		if !blocked {
+			queue = append(queue, successors(b, edges)...)
+		}
+	}
+	return true
+}
+
+// successors returns the successors of a block that are not reached
+// through one of the given edges.
+func successors(b *ssa.BasicBlock, excluded map[edge]bool) []*ssa.BasicBlock {
+	var succs []*ssa.BasicBlock
+	for _, s := range b.Succs {
+		if !excluded[edge{from: b, to: s}] {
+			succs = append(succs, s)
+		}
+	}
+	return succs
+}
@@ -32,16 +32,19 @@ var testAnalyzer = &analysis.Analyzer{
 
 func run(pass *analysis.Pass) (interface{}, error) {
 	in := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
-	var sanitizers []Sanitizer
+	var sanitizers []Dominator
 	var sinks []*ssa.Call
 	for _, fn := range in.SrcFuncs {
 		for _, b := range fn.Blocks {
 			for _, i := range b.Instrs {
-				if c, ok := i.(*ssa.Call); ok {
-					name := c.Call.StaticCallee().Name()
-					switch name {
+				if c, ok := i.(*ssa.Call); ok && c.Call.StaticCallee() != nil {
+					switch c.Call.StaticCallee().Name() {
 					case "scrub":
 						sanitizers = append(sanitizers, Sanitizer{c})
+					case "isSafe", "check", "validate":
+						if v, ok := NewValidator(c); ok {
+							sanitizers = append(sanitizers, v)
+						}
 					case "Print":
 						sinks = append(sinks, c)
 					}
@@ -51,11 +54,16 @@ func run(pass *analysis.Pass) (interface{}, error) {
 	}
 
 	for _, sink := range sinks {
+		dominated := false
 		for _, san := range sanitizers {
 			if san.Dominates(sink) {
 				pass.Reportf(sink.Pos(), "dominated")
+				dominated = true
 			}
 		}
+		if !dominated && Covers(sanitizers, nil, sink) {
+			pass.Reportf(sink.Pos(), "covered")
+		}
 	}
 	return nil, nil
 }
@@ -0,0 +1,77 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"log"
+	"time"
+)
+
+func coveredByBothBranches() {
+	pwd := "password"
+	if time.Now().Weekday() == time.Monday {
+		pwd = scrub(pwd)
+	} else {
+		pwd = scrub(pwd)
+	}
+	log.Print(pwd) // want "covered"
+}
+
+func notCoveredByOneBranch() {
+	pwd := "password"
+	if time.Now().Weekday() == time.Monday {
+		pwd = scrub(pwd)
+	}
+	log.Print(pwd)
+}
+
+func coveredByEverySwitchCase() {
+	pwd := "password"
+	switch time.Now().Weekday() {
+	case time.Monday:
+		pwd = scrub(pwd)
+	case time.Tuesday:
+		pwd = scrub(pwd)
+	default:
+		pwd = scrub(pwd)
+	}
+	log.Print(pwd) // want "covered"
+}
+
+func notCoveredWithoutSwitchDefault() {
+	pwd := "password"
+	switch time.Now().Weekday() {
+	case time.Monday:
+		pwd = scrub(pwd)
+	case time.Tuesday:
+		pwd = scrub(pwd)
+	}
+	log.Print(pwd)
+}
+
+func coveredByValidatorOrSanitizer(pwd string) {
+	if !isSafe(pwd) {
+		pwd = scrub(pwd)
+	}
+	log.Print(pwd) // want "covered"
+}
+
+func notCoveredWhenLoopIsSkipped() {
+	pwd := "password"
+	for i := 0; i < 3; i++ {
+		pwd = scrub(pwd)
+	}
+	log.Print(pwd)
+}
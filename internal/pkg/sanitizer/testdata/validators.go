@@ -0,0 +1,86 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"errors"
+	"log"
+)
+
+func guardedByTrueValidator(pwd string) {
+	if isSafe(pwd) {
+		log.Print(pwd) // want "dominated"
+	}
+	log.Print(pwd)
+}
+
+func guardedByFalseValidator(pwd string) {
+	if !isSafe(pwd) {
+		log.Print(pwd)
+		return
+	}
+	log.Print(pwd) // want "dominated"
+}
+
+func guardedByEarlyReturn(pwd string) {
+	if !isSafe(pwd) {
+		return
+	}
+	if len(pwd) > 3 {
+		log.Print(pwd) // want "dominated"
+	}
+}
+
+func guardedByNilError(pwd string) {
+	if err := check(pwd); err != nil {
+		log.Print(pwd)
+		return
+	}
+	log.Print(pwd) // want "dominated"
+}
+
+func guardedByEqualNilError(pwd string) {
+	if validate(pwd) == nil {
+		log.Print(pwd) // want "dominated"
+	}
+}
+
+func notGuardedAfterJoin(pwd string) {
+	ok := isSafe(pwd)
+	if ok {
+		pwd += "!"
+	}
+	log.Print(pwd)
+}
+
+func notGuardedWhenResultIgnored(pwd string) {
+	isSafe(pwd)
+	log.Print(pwd)
+}
+
+func isSafe(in string) bool {
+	return len(in) == 0
+}
+
+func check(in string) error {
+	return validate(in)
+}
+
+func validate(in string) error {
+	if len(in) == 0 {
+		return nil
+	}
+	return errors.New("unsafe")
+}
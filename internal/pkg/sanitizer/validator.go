@@ -0,0 +1,159 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sanitizer
+
+import (
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// Validator removes the taint of the arguments of a call that checks them,
+// e.g. by returning a bool that is true, or an error that is nil, when the
+// arguments are safe. The arguments are only clean where the check succeeded.
+type Validator struct {
+	// Call is the underlying call that performs validation
+	Call *ssa.Call
+	// edges are the control flow edges taken when the validation succeeds.
+	edges []edge
+}
+
+// An edge is a control flow edge from the block of an If instruction.
+type edge struct {
+	from, to *ssa.BasicBlock
+}
+
+// NewValidator returns the Validator for a call to a validator, if its
+// result is a bool or an error, or a tuple ending with one, and the result
+// guards some blocks.
+func NewValidator(call *ssa.Call) (Validator, bool) {
+	results := call.Call.Signature().Results()
+	if results.Len() == 0 {
+		return Validator{}, false
+	}
+	last := results.At(results.Len() - 1).Type()
+	verdict := ssa.Value(call)
+	if results.Len() > 1 {
+		verdict = extract(call, results.Len()-1)
+		if verdict == nil {
+			return Validator{}, false
+		}
+	}
+
+	v := Validator{Call: call}
+	switch {
+	case isBool(last):
+		// The arguments are safe if the result is true.
+		v.edges = guardedEdges(verdict, true)
+	case isError(last):
+		// The arguments are safe if the error is nil.
+		for _, r := range *verdict.Referrers() {
+			if cmp, ok := r.(*ssa.BinOp); ok && isNilComparison(cmp, verdict) {
+				v.edges = append(v.edges, guardedEdges(cmp, cmp.Op == token.EQL)...)
+			}
+		}
+	}
+	return v, len(v.edges) > 0
+}
+
+// Dominates returns true if the Validator's check must have succeeded when
+// the supplied instruction is executed, i.e. if the instruction is dominated
+// by an edge taken when the check succeeds.
+func (v Validator) Dominates(target ssa.Instruction) bool {
+	if v.Call.Parent() != target.Parent() {
+		// Instructions are in different functions.
+		return false
+	}
+	for _, e := range v.edges {
+		if e.dominates(target.Block()) {
+			return true
+		}
+	}
+	return false
+}
+
+// dominates determines whether every path from the function's entry to a block
+// goes through the edge. The edge's target must dominate the block, and every
+// other way into the target must go through the target itself, i.e. be a back edge.
+func (e edge) dominates(b *ssa.BasicBlock) bool {
+	if !e.to.Dominates(b) {
+		return false
+	}
+	for _, p := range e.to.Preds {
+		if p != e.from && !e.to.Dominates(p) {
+			return false
+		}
+	}
+	return true
+}
+
+// guardedEdges returns the edges taken by the If instructions using
+// a boolean value when the value equals want, looking through negations.
+func guardedEdges(cond ssa.Value, want bool) []edge {
+	var edges []edge
+	for _, r := range *cond.Referrers() {
+		switch r := r.(type) {
+		case *ssa.If:
+			// Both successors of an If may be the same block, in which case
+			// entering it does not depend on the condition.
+			if r.Block().Succs[0] == r.Block().Succs[1] {
+				continue
+			}
+			succ := r.Block().Succs[0]
+			if !want {
+				succ = r.Block().Succs[1]
+			}
+			edges = append(edges, edge{from: r.Block(), to: succ})
+		case *ssa.UnOp:
+			if r.Op == token.NOT {
+				edges = append(edges, guardedEdges(r, !want)...)
+			}
+		}
+	}
+	return edges
+}
+
+// extract returns the value extracted from a tuple at a given index, if any.
+func extract(tuple ssa.Value, index int) ssa.Value {
+	for _, r := range *tuple.Referrers() {
+		if e, ok := r.(*ssa.Extract); ok && e.Index == index {
+			return e
+		}
+	}
+	return nil
+}
+
+// isNilComparison determines whether a binary operation compares a value with nil.
+func isNilComparison(op *ssa.BinOp, v ssa.Value) bool {
+	if op.Op != token.EQL && op.Op != token.NEQ {
+		return false
+	}
+	other := op.Y
+	if op.Y == v {
+		other = op.X
+	}
+	c, ok := other.(*ssa.Const)
+	return ok && c.Value == nil
+}
+
+func isBool(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Info()&types.IsBoolean != 0
+}
+
+func isError(t types.Type) bool {
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
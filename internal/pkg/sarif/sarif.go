@@ -0,0 +1,190 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sarif defines the subset of the Static Analysis Results Interchange
+// Format (SARIF) 2.1.0 used to report findings.
+// See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+package sarif
+
+import (
+	"encoding/json"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// Version is the version of the format.
+	Version = "2.1.0"
+	// Schema is the location of the JSON schema of the format.
+	Schema = "https://json.schemastore.org/sarif-2.1.0.json"
+	// SrcRoot is the base of the URIs of the files within the root directory of a run.
+	SrcRoot = "%SRCROOT%"
+)
+
+// Log is the top-level object of a SARIF file.
+type Log struct {
+	Version string `json:"version"`
+	Schema  string `json:"$schema"`
+	Runs    []Run  `json:"runs"`
+}
+
+// Run holds the results produced by a tool in a single invocation.
+type Run struct {
+	Tool               Tool                        `json:"tool"`
+	OriginalURIBaseIDs map[string]ArtifactLocation `json:"originalUriBaseIds,omitempty"`
+	Results            []Result                    `json:"results"`
+}
+
+// Tool describes the tool that produced the results.
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+// Driver describes the main component of a tool, along with its rules.
+type Driver struct {
+	Name           string `json:"name"`
+	InformationURI string `json:"informationUri,omitempty"`
+	Rules          []Rule `json:"rules,omitempty"`
+}
+
+// Rule describes a kind of result.
+type Rule struct {
+	ID               string   `json:"id"`
+	ShortDescription *Message `json:"shortDescription,omitempty"`
+	FullDescription  *Message `json:"fullDescription,omitempty"`
+}
+
+// Message is a user-facing text.
+type Message struct {
+	Text string `json:"text"`
+}
+
+// Result is a single finding.
+type Result struct {
+	RuleID           string     `json:"ruleId"`
+	Level            string     `json:"level,omitempty"`
+	Message          Message    `json:"message"`
+	Locations        []Location `json:"locations"`
+	RelatedLocations []Location `json:"relatedLocations,omitempty"`
+	CodeFlows        []CodeFlow `json:"codeFlows,omitempty"`
+	// PartialFingerprints identify a result across runs.
+	PartialFingerprints map[string]string      `json:"partialFingerprints,omitempty"`
+	Properties          map[string]interface{} `json:"properties,omitempty"`
+}
+
+// Location is a location in a file, along with an optional message.
+type Location struct {
+	PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+	Message          *Message         `json:"message,omitempty"`
+}
+
+// PhysicalLocation is a region within a file.
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           Region           `json:"region"`
+}
+
+// ArtifactLocation identifies a file. If URIBaseID is set,
+// URI is relative to the base that it names.
+type ArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+// Region is a position within a file. Lines and columns start at 1.
+type Region struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// CodeFlow is the path taken by data from one location to another.
+type CodeFlow struct {
+	ThreadFlows []ThreadFlow `json:"threadFlows"`
+}
+
+// ThreadFlow is the sequence of locations visited along a CodeFlow.
+type ThreadFlow struct {
+	Locations []ThreadFlowLocation `json:"locations"`
+}
+
+// ThreadFlowLocation is a location visited along a ThreadFlow.
+type ThreadFlowLocation struct {
+	Location Location `json:"location"`
+}
+
+// NewLog returns a log holding a single run of a tool.
+// Files within the root directory are identified relative to SrcRoot.
+func NewLog(driver Driver, root string, results []Result) *Log {
+	if results == nil {
+		// An empty run has an empty list of results, rather than none.
+		results = []Result{}
+	}
+	return &Log{
+		Version: Version,
+		Schema:  Schema,
+		Runs: []Run{{
+			Tool: Tool{Driver: driver},
+			OriginalURIBaseIDs: map[string]ArtifactLocation{
+				SrcRoot: {URI: fileURI(root) + "/"},
+			},
+			Results: results,
+		}},
+	}
+}
+
+// NewLocation returns the location of a position, with an optional message.
+// Files within the root directory are identified relative to SrcRoot,
+// and other files by their absolute path.
+func NewLocation(pos token.Position, root, message string) Location {
+	loc := Location{
+		PhysicalLocation: PhysicalLocation{
+			ArtifactLocation: artifactLocation(pos.Filename, root),
+			Region: Region{
+				StartLine:   pos.Line,
+				StartColumn: pos.Column,
+			},
+		},
+	}
+	if message != "" {
+		loc.Message = &Message{Text: message}
+	}
+	return loc
+}
+
+func artifactLocation(filename, root string) ArtifactLocation {
+	if rel, err := filepath.Rel(root, filename); err == nil && !strings.HasPrefix(rel, "..") {
+		return ArtifactLocation{URI: filepath.ToSlash(rel), URIBaseID: SrcRoot}
+	}
+	return ArtifactLocation{URI: fileURI(filename)}
+}
+
+func fileURI(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		// Windows paths start with a drive letter.
+		path = "/" + path
+	}
+	return "file://" + path
+}
+
+// WriteFile writes a log to a file in JSON.
+func (l *Log) WriteFile(filename string) error {
+	b, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, append(b, '\n'), 0644)
+}
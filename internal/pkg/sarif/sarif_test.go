@@ -0,0 +1,70 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sarif
+
+import (
+	"encoding/json"
+	"go/token"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewLocation(t *testing.T) {
+	testCases := []struct {
+		desc string
+		pos  token.Position
+		want ArtifactLocation
+	}{
+		{
+			desc: "Files within the root are relative to the source root",
+			pos:  token.Position{Filename: "/src/project/pkg/file.go", Line: 3, Column: 2},
+			want: ArtifactLocation{URI: "pkg/file.go", URIBaseID: SrcRoot},
+		},
+		{
+			desc: "Files outside the root are absolute",
+			pos:  token.Position{Filename: "/src/other/file.go", Line: 3, Column: 2},
+			want: ArtifactLocation{URI: "file:///src/other/file.go"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			loc := NewLocation(tc.pos, "/src/project", "")
+			if diff := cmp.Diff(tc.want, loc.PhysicalLocation.ArtifactLocation); diff != "" {
+				t.Errorf("artifact location diff (-want +got):\n%s", diff)
+			}
+			if got := loc.PhysicalLocation.Region; got.StartLine != 3 || got.StartColumn != 2 {
+				t.Errorf("got region %v, want line 3, column 2", got)
+			}
+		})
+	}
+}
+
+func TestEmptyLogHasEmptyResults(t *testing.T) {
+	b, err := json.Marshal(NewLog(Driver{Name: "levee"}, "/src/project", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw struct {
+		Runs []map[string]json.RawMessage
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(raw.Runs[0]["results"]); got != "[]" {
+		t.Errorf("got results %s, want []", got)
+	}
+}
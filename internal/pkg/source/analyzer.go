@@ -21,6 +21,7 @@ import (
 	"github.com/google/go-flow-levee/internal/pkg/config"
 	"github.com/google/go-flow-levee/internal/pkg/fieldpropagator"
 	"github.com/google/go-flow-levee/internal/pkg/fieldtags"
+	infer "github.com/google/go-flow-levee/internal/pkg/sourceinfer"
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/buildssa"
 	"golang.org/x/tools/go/ssa"
@@ -28,26 +29,37 @@ import (
 
 type ResultType = map[*ssa.Function][]*Source
 
-var Analyzer = &analysis.Analyzer{
-	Name:       "source",
-	Doc:        "This analyzer identifies ssa.Values that are sources.",
-	Flags:      config.FlagSet,
-	Run:        run,
-	Requires:   []*analysis.Analyzer{buildssa.Analyzer, fieldtags.Analyzer, fieldpropagator.Analyzer},
-	ResultType: reflect.TypeOf(new(ResultType)).Elem(),
+// Analyzer reads its configuration from the file selected by the -config flag.
+var Analyzer = NewAnalyzer(nil, fieldtags.Analyzer, infer.Analyzer, fieldpropagator.Analyzer)
+
+// NewAnalyzer returns an analyzer bound to conf, which requires the taggedFields,
+// inferred and propagators analyzers bound to the same configuration.
+// If conf is nil, the analyzer reads its configuration as Analyzer does.
+func NewAnalyzer(conf *config.Config, taggedFields, inferred, propagators *analysis.Analyzer) *analysis.Analyzer {
+	return &analysis.Analyzer{
+		Name:  "source",
+		Doc:   "This analyzer identifies ssa.Values that are sources.",
+		Flags: config.NewFlagSet(conf),
+		Run: func(pass *analysis.Pass) (interface{}, error) {
+			taggedFields := pass.ResultOf[taggedFields].(fieldtags.ResultType)
+			inferredSources := pass.ResultOf[inferred].(infer.ResultType)
+			fieldPropagators := pass.ResultOf[propagators].(fieldpropagator.ResultType)
+			return run(pass, conf, taggedFields, inferredSources, fieldPropagators)
+		},
+		Requires:   []*analysis.Analyzer{buildssa.Analyzer, taggedFields, inferred, propagators},
+		ResultType: reflect.TypeOf(new(ResultType)).Elem(),
+	}
 }
 
-func run(pass *analysis.Pass) (interface{}, error) {
+func run(pass *analysis.Pass, conf *config.Config, taggedFields fieldtags.ResultType, inferredSources infer.ResultType, fieldPropagators fieldpropagator.ResultType) (interface{}, error) {
 	ssaInput := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
-	taggedFields := pass.ResultOf[fieldtags.Analyzer].(fieldtags.ResultType)
-	fieldPropagators := pass.ResultOf[fieldpropagator.Analyzer].(fieldpropagator.ResultType)
 
-	conf, err := config.ReadConfig()
+	conf, err := config.Load(conf, pass.Pkg.Path())
 	if err != nil {
 		return nil, err
 	}
 
-	sourceMap := identify(conf, ssaInput, taggedFields, fieldPropagators)
+	sourceMap := identify(conf, ssaInput, taggedFields, inferredSources, fieldPropagators)
 
 	for _, srcs := range sourceMap {
 		for _, s := range srcs {
@@ -22,6 +22,7 @@ import (
 	"github.com/google/go-flow-levee/internal/pkg/config"
 	"github.com/google/go-flow-levee/internal/pkg/fieldpropagator"
 	"github.com/google/go-flow-levee/internal/pkg/fieldtags"
+	infer "github.com/google/go-flow-levee/internal/pkg/sourceinfer"
 	"github.com/google/go-flow-levee/internal/pkg/sourcetype"
 	"github.com/google/go-flow-levee/internal/pkg/utils"
 	"golang.org/x/tools/go/analysis/passes/buildssa"
@@ -32,6 +33,12 @@ import (
 // starting point in a propagation analysis.
 type Source struct {
 	Node ssa.Node
+	// Labels are the labels of the configured sources matching the Source.
+	Labels []string
+	// Entries are the names of the configured sources matching the Source,
+	// e.g. "Sources[0]". Sources identified through field tags only
+	// have no entries.
+	Entries []string
 }
 
 // Pos returns the token position of the SSA Node associated with the Source.
@@ -55,16 +62,17 @@ func (s *Source) Pos() token.Pos {
 }
 
 // New constructs a new Source.
-func New(in ssa.Node) *Source {
+func New(in ssa.Node, labels []string) *Source {
 	return &Source{
-		Node: in,
+		Node:   in,
+		Labels: labels,
 	}
 }
 
 // identify individually examines each Function in the SSA code looking for Sources.
 // It produces a map relating a Function to the Sources it contains.
 // If a Function contains no Sources, it does not appear in the map.
-func identify(conf *config.Config, ssaInput *buildssa.SSA, taggedFields fieldtags.ResultType, propagators fieldpropagator.ResultType) map[*ssa.Function][]*Source {
+func identify(conf *config.Config, ssaInput *buildssa.SSA, taggedFields fieldtags.ResultType, inferred infer.ResultType, propagators fieldpropagator.ResultType) map[*ssa.Function][]*Source {
 	sourceMap := make(map[*ssa.Function][]*Source)
 
 	for _, fn := range ssaInput.SrcFuncs {
@@ -75,9 +83,9 @@ func identify(conf *config.Config, ssaInput *buildssa.SSA, taggedFields fieldtag
 		}
 
 		var sources []*Source
-		sources = append(sources, sourcesFromParams(fn, conf, taggedFields)...)
-		sources = append(sources, sourcesFromClosures(fn, conf, taggedFields)...)
-		sources = append(sources, sourcesFromBlocks(fn, conf, taggedFields, propagators)...)
+		sources = append(sources, sourcesFromParams(fn, conf, taggedFields, inferred)...)
+		sources = append(sources, sourcesFromClosures(fn, conf, taggedFields, inferred)...)
+		sources = append(sources, sourcesFromBlocks(fn, conf, taggedFields, inferred, propagators)...)
 
 		if len(sources) > 0 {
 			sourceMap[fn] = sources
@@ -87,11 +95,13 @@ func identify(conf *config.Config, ssaInput *buildssa.SSA, taggedFields fieldtag
 }
 
 // sourcesFromParams identifies Sources that appear within a Function's parameters.
-func sourcesFromParams(fn *ssa.Function, conf *config.Config, taggedFields fieldtags.ResultType) []*Source {
+func sourcesFromParams(fn *ssa.Function, conf *config.Config, taggedFields fieldtags.ResultType, inferred infer.ResultType) []*Source {
 	var sources []*Source
 	for _, p := range fn.Params {
-		if sourcetype.IsSourceType(conf, taggedFields, p.Type()) {
-			sources = append(sources, New(p))
+		if sourcetype.IsSourceType(conf, taggedFields, inferred, p.Type()) {
+			s := New(p, sourcetype.Labels(conf, inferred, p.Type()))
+			s.Entries = sourcetype.Entries(conf, inferred, p.Type())
+			sources = append(sources, s)
 		}
 	}
 	return sources
@@ -101,30 +111,71 @@ func sourcesFromParams(fn *ssa.Function, conf *config.Config, taggedFields field
 // A value that is captured by a closure will appear as a Free Variable in the
 // closure. In the SSA, a Free Variable is represented as a Pointer, distinct
 // from the original value.
-func sourcesFromClosures(fn *ssa.Function, conf *config.Config, taggedFields fieldtags.ResultType) []*Source {
+func sourcesFromClosures(fn *ssa.Function, conf *config.Config, taggedFields fieldtags.ResultType, inferred infer.ResultType) []*Source {
 	var sources []*Source
 	for _, fv := range fn.FreeVars {
-		if ptr, ok := fv.Type().(*types.Pointer); ok && sourcetype.IsSourceType(conf, taggedFields, ptr) {
-			sources = append(sources, New(fv))
+		if ptr, ok := fv.Type().(*types.Pointer); ok && sourcetype.IsSourceType(conf, taggedFields, inferred, ptr) {
+			s := New(fv, sourcetype.Labels(conf, inferred, ptr))
+			s.Entries = sourcetype.Entries(conf, inferred, ptr)
+			sources = append(sources, s)
 		}
 	}
 	return sources
 }
 
 // sourcesFromBlocks finds Source values created by instructions within a function's body.
-func sourcesFromBlocks(fn *ssa.Function, conf *config.Config, taggedFields fieldtags.ResultType, propagators fieldpropagator.ResultType) []*Source {
+func sourcesFromBlocks(fn *ssa.Function, conf *config.Config, taggedFields fieldtags.ResultType, inferred infer.ResultType, propagators fieldpropagator.ResultType) []*Source {
 	var sources []*Source
 	for _, b := range fn.Blocks {
 		for _, instr := range b.Instrs {
-			if n := instr.(ssa.Node); isSourceNode(n, conf, propagators, taggedFields) {
-				sources = append(sources, New(n))
+			if n := instr.(ssa.Node); isSourceNode(n, conf, propagators, taggedFields, inferred) {
+				labels, entries := labelsAndEntries(n, conf, inferred, propagators)
+				s := New(n, labels)
+				s.Entries = entries
+				sources = append(sources, s)
 			}
 		}
 	}
 	return sources
 }
 
-func isSourceNode(n ssa.Node, conf *config.Config, propagators fieldpropagator.ResultType, taggedFields fieldtags.ResultType) bool {
+// labelsAndEntries returns the labels of a Source node identified by isSourceNode,
+// and the names of the configured sources that it matches.
+func labelsAndEntries(n ssa.Node, conf *config.Config, inferred infer.ResultType, propagators fieldpropagator.ResultType) (labels, entries []string) {
+	switch v := n.(type) {
+	case *ssa.Call:
+		if IsSourceFunctionResult(v, conf) {
+			return sourceFunctionResult(conf, &v.Call, 0)
+		}
+		// A field propagator returns a field of its receiver,
+		// so the receiver's labels are used.
+		if recv := v.Call.Signature().Recv(); recv != nil && propagators.IsFieldPropagator(v) {
+			return sourceType(conf, inferred, recv.Type())
+		}
+	case *ssa.TypeAssert:
+		return sourceType(conf, inferred, v.AssertedType)
+	case *ssa.Extract:
+		if IsSourceFunctionResult(v, conf) {
+			return sourceFunctionResult(conf, v.Tuple.(*ssa.Call).Common(), v.Index)
+		}
+		return sourceType(conf, inferred, v.Tuple.Type().(*types.Tuple).At(v.Index).Type())
+	}
+	return sourceType(conf, inferred, n.(ssa.Value).Type())
+}
+
+// sourceType returns the labels of a source type, and the names of the configured sources that it matches.
+func sourceType(conf *config.Config, inferred infer.ResultType, t types.Type) (labels, entries []string) {
+	return sourcetype.Labels(conf, inferred, t), sourcetype.Entries(conf, inferred, t)
+}
+
+// sourceFunctionResult returns the labels of a result of a call to a source function,
+// and the names of the configured source functions that it matches.
+func sourceFunctionResult(conf *config.Config, call *ssa.CallCommon, index int) (labels, entries []string) {
+	path, recv, name, _ := utils.DecomposeCallee(call)
+	return conf.SourceFunctionLabels(path, recv, name, index), conf.SourceFunctionEntries(path, recv, name, index)
+}
+
+func isSourceNode(n ssa.Node, conf *config.Config, propagators fieldpropagator.ResultType, taggedFields fieldtags.ResultType, inferred infer.ResultType) bool {
 	switch v := n.(type) {
 	// All sources are explicitly identified.
 	default:
@@ -132,32 +183,34 @@ func isSourceNode(n ssa.Node, conf *config.Config, propagators fieldpropagator.R
 
 	// Values produced by sanitizers are not sources.
 	case *ssa.Alloc:
-		return !isProducedBySanitizer(v, conf) && sourcetype.IsSourceType(conf, taggedFields, n.(ssa.Value).Type())
+		return !isProducedBySanitizer(v, conf) && sourcetype.IsSourceType(conf, taggedFields, inferred, n.(ssa.Value).Type())
 
 	// Values produced by sanitizers are not sources.
 	// Values produced by field propagators are.
+	// Values returned by source functions are.
 	case *ssa.Call:
 		return !isProducedBySanitizer(v, conf) &&
-			(propagators.IsFieldPropagator(v) || sourcetype.IsSourceType(conf, taggedFields, n.(ssa.Value).Type()))
+			(propagators.IsFieldPropagator(v) || IsSourceFunctionResult(v, conf) || sourcetype.IsSourceType(conf, taggedFields, inferred, n.(ssa.Value).Type()))
 
 	// A type assertion can assert that an interface is of a source type.
 	// Only panicky type asserts will refer to the source Value.
 	// The typed value returned in (value, ok) type assertions are examined in the case for ssa.Extract instructions.
 	case *ssa.TypeAssert:
-		return !v.CommaOk && sourcetype.IsSourceType(conf, taggedFields, v.AssertedType)
+		return !v.CommaOk && sourcetype.IsSourceType(conf, taggedFields, inferred, v.AssertedType)
 
 	// An Extract is used to obtain a value from an instruction that returns multiple values.
 	// In some cases, an extracted value isn't tied to any other instruction that could be used
 	// to detect a source value, so the Extract itself has to be used. Specifically:
 	// - If the extracted value is a Pointer to a Source
 	// - If the extracted value is inlined into a call
+	// - If the extracted value is returned by a source function
 	case *ssa.Extract:
 		t := v.Tuple.Type().(*types.Tuple).At(v.Index).Type()
-		return sourcetype.IsSourceType(conf, taggedFields, t)
+		return IsSourceFunctionResult(v, conf) || sourcetype.IsSourceType(conf, taggedFields, inferred, t)
 
 	// Unary operator <- can receive sources from a channel.
 	case *ssa.UnOp:
-		return v.Op == token.ARROW && sourcetype.IsSourceType(conf, taggedFields, n.(ssa.Value).Type())
+		return v.Op == token.ARROW && sourcetype.IsSourceType(conf, taggedFields, inferred, n.(ssa.Value).Type())
 
 	// Field access (Field, FieldAddr),
 	// collection access (Index, IndexAddr, Lookup),
@@ -166,10 +219,32 @@ func isSourceNode(n ssa.Node, conf *config.Config, propagators fieldpropagator.R
 	case *ssa.Field, *ssa.FieldAddr,
 		*ssa.Index, *ssa.IndexAddr, *ssa.Lookup,
 		*ssa.MakeMap, *ssa.MakeChan:
-		return sourcetype.IsSourceType(conf, taggedFields, n.(ssa.Value).Type())
+		return sourcetype.IsSourceType(conf, taggedFields, inferred, n.(ssa.Value).Type())
 	}
 }
 
+// IsSourceFunctionResult determines whether a value is a result of a call
+// to a source function, and the configuration identifies that result as a source.
+// For calls that return multiple values, only the Extracts are considered.
+func IsSourceFunctionResult(v ssa.Value, conf *config.Config) bool {
+	switch v := v.(type) {
+	case *ssa.Call:
+		if v.Call.Signature().Results().Len() != 1 {
+			return false
+		}
+		return isSourceFunctionCall(&v.Call, 0, conf)
+	case *ssa.Extract:
+		call, ok := v.Tuple.(*ssa.Call)
+		return ok && isSourceFunctionCall(&call.Call, v.Index, conf)
+	}
+	return false
+}
+
+func isSourceFunctionCall(c *ssa.CallCommon, index int, conf *config.Config) bool {
+	path, recv, name, ok := utils.DecomposeCallee(c)
+	return ok && conf.IsSourceFunctionResult(path, recv, name, index)
+}
+
 func isProducedBySanitizer(v ssa.Value, conf *config.Config) bool {
 	for _, instr := range *v.Referrers() {
 		store, ok := instr.(*ssa.Store)
@@ -33,8 +33,15 @@ import (
 )
 
 // ResultType is a set of types.Object that are inferred Sources.
+// It is empty unless source inference is enabled in the configuration.
 type ResultType map[types.Object]bool
 
+// IsSourceType determines whether a type is an inferred Source type.
+func (r ResultType) IsSourceType(t types.Type) bool {
+	n, ok := t.(*types.Named)
+	return ok && r[n.Obj()]
+}
+
 type inferredSourceFact struct{}
 
 func (i inferredSourceFact) AFact() {}
@@ -43,9 +50,7 @@ func (i inferredSourceFact) String() string {
 	return "inferred source"
 }
 
-var Analyzer = &analysis.Analyzer{
-	Name: "sourceinfer",
-	Doc: `This analyzer infers named source types from typedefs and fields.
+const doc = `This analyzer infers named source types from typedefs and fields.
 
 Suppose Foo has been configured to be a source type.
 
@@ -68,31 +73,51 @@ the sourceyness of types that depend on them. For example, in the
 below type definitions, both Qux and Quux will be identified as sources:
 type Qux Foo
 type Quux Qux
-`,
-	Run: run,
-	Requires: []*analysis.Analyzer{
-		fieldtags.Analyzer,
-		inspect.Analyzer,
-	},
-	ResultType: reflect.TypeOf(new(ResultType)).Elem(),
-	FactTypes:  []analysis.Fact{new(inferredSourceFact)},
+`
+
+// Analyzer reads its configuration from the file selected by the -config flag.
+var Analyzer = NewAnalyzer(nil, fieldtags.Analyzer)
+
+// NewAnalyzer returns an analyzer bound to conf, which requires the taggedFields
+// analyzer bound to the same configuration. If conf is nil,
+// the analyzer reads its configuration as Analyzer does.
+func NewAnalyzer(conf *config.Config, taggedFields *analysis.Analyzer) *analysis.Analyzer {
+	return &analysis.Analyzer{
+		Name: "sourceinfer",
+		Doc:  doc,
+		Run: func(pass *analysis.Pass) (interface{}, error) {
+			return run(pass, conf, pass.ResultOf[taggedFields].(fieldtags.ResultType))
+		},
+		Requires: []*analysis.Analyzer{
+			taggedFields,
+			inspect.Analyzer,
+		},
+		ResultType: reflect.TypeOf(new(ResultType)).Elem(),
+		FactTypes:  []analysis.Fact{new(inferredSourceFact)},
+	}
 }
 
 type objectGraph map[types.Object][]types.Object
 
-func run(pass *analysis.Pass) (interface{}, error) {
-	conf, err := config.ReadConfig()
+func run(pass *analysis.Pass, conf *config.Config, ft fieldtags.ResultType) (interface{}, error) {
+	conf, err := config.Load(conf, pass.Pkg.Path())
 	if err != nil {
 		return nil, err
 	}
 
 	ins := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
-	ft := pass.ResultOf[fieldtags.Analyzer].(fieldtags.ResultType)
 
 	objectGraph := createObjectGraph(pass, ins)
 
 	inferredSources := inferSources(pass, conf, ft, objectGraph)
+	if !conf.InferSources {
+		return ResultType{}, nil
+	}
 
+	// Include the sources inferred in dependencies.
+	for _, f := range pass.AllObjectFacts() {
+		inferredSources[f.Object] = true
+	}
 	return inferredSources, nil
 }
 
@@ -21,23 +21,25 @@ import (
 
 	"github.com/google/go-flow-levee/internal/pkg/config"
 	"github.com/google/go-flow-levee/internal/pkg/fieldtags"
+	infer "github.com/google/go-flow-levee/internal/pkg/sourceinfer"
 	"github.com/google/go-flow-levee/internal/pkg/utils"
 )
 
 // IsSourceType determines whether a Type is a Source Type.
 // A Source Type is either:
 // - A Named Struct Type that is configured as a Source
+// - A Named Type that is inferred to be a Source
 // - A Struct Type that contains a tagged field
 // - A composite type that contains a Source Type
-func IsSourceType(c *config.Config, tf fieldtags.ResultType, t types.Type) bool {
+func IsSourceType(c *config.Config, tf fieldtags.ResultType, inferred infer.ResultType, t types.Type) bool {
 	seen := map[types.Type]bool{}
-	return isSourceType(c, tf, t, seen)
+	return isSourceType(c, tf, inferred, t, seen)
 }
 
 // isSourceType is a helper method for IsSourceType.
 // The set of seen types is kept track of to prevent infinite recursion on
 // types such as `type A map[string]A`, which refer to themselves.
-func isSourceType(c *config.Config, tf fieldtags.ResultType, t types.Type, seen map[types.Type]bool) bool {
+func isSourceType(c *config.Config, tf fieldtags.ResultType, inferred infer.ResultType, t types.Type, seen map[types.Type]bool) bool {
 	// If a type has been seen, then its status as a Source has already
 	// been evaluated. Return to avoid infinite recursion.
 	if seen[t] {
@@ -47,19 +49,19 @@ func isSourceType(c *config.Config, tf fieldtags.ResultType, t types.Type, seen
 
 	switch tt := t.(type) {
 	case *types.Named:
-		return c.IsSourceType(utils.DecomposeType(tt)) || isSourceType(c, tf, tt.Underlying(), seen)
+		return c.IsSourceType(utils.DecomposeType(tt)) || inferred.IsSourceType(tt) || isSourceType(c, tf, inferred, tt.Underlying(), seen)
 	case *types.Array:
-		return isSourceType(c, tf, tt.Elem(), seen)
+		return isSourceType(c, tf, inferred, tt.Elem(), seen)
 	case *types.Slice:
-		return isSourceType(c, tf, tt.Elem(), seen)
+		return isSourceType(c, tf, inferred, tt.Elem(), seen)
 	case *types.Chan:
-		return isSourceType(c, tf, tt.Elem(), seen)
+		return isSourceType(c, tf, inferred, tt.Elem(), seen)
 	case *types.Map:
-		key := isSourceType(c, tf, tt.Key(), seen)
-		elem := isSourceType(c, tf, tt.Elem(), seen)
+		key := isSourceType(c, tf, inferred, tt.Key(), seen)
+		elem := isSourceType(c, tf, inferred, tt.Elem(), seen)
 		return key || elem
 	case *types.Pointer:
-		return isSourceType(c, tf, tt.Elem(), seen)
+		return isSourceType(c, tf, inferred, tt.Elem(), seen)
 	case *types.Struct:
 		return hasTaggedField(tf, tt)
 	case *types.Basic, *types.Tuple, *types.Interface, *types.Signature:
@@ -72,6 +74,80 @@ func isSourceType(c *config.Config, tf fieldtags.ResultType, t types.Type, seen
 	}
 }
 
+// Labels returns the labels of the configured sources that make a type
+// a Source Type. Structs inferred to be Source Types have the labels of the
+// source types held by their fields. A type defined from a source type, such
+// as Bar in `type Bar Foo`, does not refer to Foo, so it does not have Foo's labels.
+// If no configured source makes the type a Source Type, e.g. because the type
+// contains a tagged field, the default label is returned.
+func Labels(c *config.Config, inferred infer.ResultType, t types.Type) []string {
+	var labels []string
+	collect(c.SourceTypeLabels, inferred, t, map[types.Type]bool{}, &labels)
+	if len(labels) == 0 {
+		return []string{config.DefaultLabel}
+	}
+	return labels
+}
+
+// Entries returns the names of the configured sources that make a type
+// a Source Type, e.g. "Sources[0]". As with Labels, structs inferred to be
+// Source Types have the entries of the source types held by their fields.
+func Entries(c *config.Config, inferred infer.ResultType, t types.Type) []string {
+	var entries []string
+	collect(c.SourceTypeEntries, inferred, t, map[types.Type]bool{}, &entries)
+	return entries
+}
+
+// collect is a helper method for Labels and Entries.
+// It visits types in the same way as isSourceType, collecting
+// the values associated with each named type. The fields of a struct
+// are only visited if the struct is an inferred Source Type.
+func collect(values func(path, name string) []string, inferred infer.ResultType, t types.Type, seen map[types.Type]bool, result *[]string) {
+	if seen[t] {
+		return
+	}
+	seen[t] = true
+
+	switch tt := t.(type) {
+	case *types.Named:
+		for _, v := range values(utils.DecomposeType(tt)) {
+			if !contains(*result, v) {
+				*result = append(*result, v)
+			}
+		}
+		st, ok := tt.Underlying().(*types.Struct)
+		if !ok {
+			collect(values, inferred, tt.Underlying(), seen, result)
+			break
+		}
+		if inferred.IsSourceType(tt) {
+			for i := 0; i < st.NumFields(); i++ {
+				collect(values, inferred, st.Field(i).Type(), seen, result)
+			}
+		}
+	case *types.Array:
+		collect(values, inferred, tt.Elem(), seen, result)
+	case *types.Slice:
+		collect(values, inferred, tt.Elem(), seen, result)
+	case *types.Chan:
+		collect(values, inferred, tt.Elem(), seen, result)
+	case *types.Map:
+		collect(values, inferred, tt.Key(), seen, result)
+		collect(values, inferred, tt.Elem(), seen, result)
+	case *types.Pointer:
+		collect(values, inferred, tt.Elem(), seen, result)
+	}
+}
+
+func contains(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
 func hasTaggedField(taggedFields fieldtags.ResultType, s *types.Struct) bool {
 	for i := 0; i < s.NumFields(); i++ {
 		f := s.Field(i)
@@ -39,7 +39,7 @@ func run(pass *analysis.Pass) (interface{}, error) {
 
 	for _, fn := range ssaInput.SrcFuncs {
 		for _, p := range fn.Params {
-			_ = IsSourceType(c, tf, p.Type())
+			_ = IsSourceType(c, tf, nil, p.Type())
 		}
 	}
 
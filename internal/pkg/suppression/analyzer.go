@@ -17,22 +17,98 @@
 package suppression
 
 import (
+	"fmt"
 	"go/ast"
+	"go/token"
 	"reflect"
 	"strings"
 
 	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
 )
 
 const suppressionString = "levee.DoNotReport"
 
-// ResultType is a set of nodes that are suppressed by a comment.
-type ResultType map[ast.Node]bool
+// A Scope is the extent of the code to which a suppression applies.
+type Scope string
 
-// IsSuppressed determines whether the given node is suppressed.
+const (
+	// NodeScope suppresses the findings at the node associated with the comment,
+	// e.g. the call on the line following the comment. This is the default.
+	NodeScope Scope = ""
+	// FunctionScope suppresses the findings within a function.
+	FunctionScope Scope = "function"
+	// FileScope suppresses the findings within a file.
+	FileScope Scope = "file"
+)
+
+// A Suppression is a suppressing comment, e.g.
+//
+//	// levee.DoNotReport(reason="the token is redacted by the logger", label=credentials)
+type Suppression struct {
+	// Pos is the position of the suppression string within the comment.
+	Pos token.Pos
+	// Reason justifies the suppression. It is empty if no reason was given.
+	Reason string
+	// Labels restricts the suppression to findings with these labels.
+	// If it is empty, the suppression applies to all findings.
+	Labels []string
+	Scope  Scope
+	// Err describes why the suppression is malformed, if it is.
+	// A malformed suppression does not suppress any findings.
+	Err error
+}
+
+// Covers determines whether the suppression applies to a finding
+// reached with the given labels, i.e. whether it applies to all of them.
+func (s *Suppression) Covers(labels []string) bool {
+	if len(s.Labels) == 0 {
+		return true
+	}
+	for _, l := range labels {
+		if !contains(s.Labels, l) {
+			return false
+		}
+	}
+	return true
+}
+
+// ResultType holds the suppressions in a package, and the nodes,
+// functions, and files to which they apply.
+type ResultType struct {
+	// All holds every suppression in the package, including malformed ones.
+	All   []*Suppression
+	nodes map[ast.Node][]*Suppression
+	// Function-scoped suppressions apply to an *ast.FuncDecl or *ast.FuncLit.
+	funcs map[ast.Node][]*Suppression
+	files map[*ast.File][]*Suppression
+}
+
+// IsSuppressed determines whether the given node is suppressed
+// by a well-formed suppression associated with it.
 func (rt ResultType) IsSuppressed(n ast.Node) bool {
-	_, ok := rt[n]
-	return ok
+	for _, s := range rt.nodes[n] {
+		if s.Err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// NodeSuppressions returns the suppressions associated with a node.
+func (rt ResultType) NodeSuppressions(n ast.Node) []*Suppression {
+	return rt.nodes[n]
+}
+
+// FunctionSuppressions returns the function-scoped suppressions of a function,
+// which is an *ast.FuncDecl or an *ast.FuncLit.
+func (rt ResultType) FunctionSuppressions(fn ast.Node) []*Suppression {
+	return rt.funcs[fn]
+}
+
+// FileSuppressions returns the file-scoped suppressions of a file.
+func (rt ResultType) FileSuppressions(f *ast.File) []*Suppression {
+	return rt.files[f]
 }
 
 var Analyzer = &analysis.Analyzer{
@@ -43,28 +119,88 @@ var Analyzer = &analysis.Analyzer{
 }
 
 func run(pass *analysis.Pass) (interface{}, error) {
-	result := map[ast.Node]bool{}
+	result := ResultType{
+		nodes: make(map[ast.Node][]*Suppression),
+		funcs: make(map[ast.Node][]*Suppression),
+		files: make(map[*ast.File][]*Suppression),
+	}
 
 	for _, f := range pass.Files {
+		// Each comment group is associated with a single node.
+		nodes := make(map[*ast.CommentGroup]ast.Node)
 		for node, commentGroups := range ast.NewCommentMap(pass.Fset, f, f.Comments) {
 			for _, cg := range commentGroups {
-				if isSuppressingCommentGroup(cg) {
-					result[node] = true
-					pass.Reportf(node.Pos(), "suppressed")
+				nodes[cg] = node
+			}
+		}
+		for _, cg := range f.Comments {
+			for _, s := range suppressions(cg) {
+				result.All = append(result.All, s)
+				if s.Err != nil {
+					continue
+				}
+				switch s.Scope {
+				case NodeScope:
+					if n, ok := nodes[cg]; ok {
+						result.nodes[n] = append(result.nodes[n], s)
+					}
+				case FunctionScope:
+					fn := enclosingFunction(f, cg, s.Pos)
+					if fn == nil {
+						s.Err = fmt.Errorf("a function-scoped suppression must document a function or be within one")
+						continue
+					}
+					result.funcs[fn] = append(result.funcs[fn], s)
+				case FileScope:
+					result.files[f] = append(result.files[f], s)
 				}
 			}
 		}
 	}
 
-	return ResultType(result), nil
+	return result, nil
+}
+
+// suppressions returns the suppressions in a comment group. A suppression
+// is a line of a comment that begins with the suppression string.
+func suppressions(commentGroup *ast.CommentGroup) []*Suppression {
+	var result []*Suppression
+	for _, c := range commentGroup.List {
+		offset := 0
+		for _, line := range strings.SplitAfter(c.Text, "\n") {
+			trimmed := strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(line), "//"), "/*"))
+			if strings.HasPrefix(trimmed, suppressionString) {
+				pos := c.Pos() + token.Pos(offset+strings.Index(line, suppressionString))
+				result = append(result, parse(pos, strings.TrimPrefix(trimmed, suppressionString)))
+			}
+			offset += len(line)
+		}
+	}
+	return result
+}
+
+// enclosingFunction returns the function to which a function-scoped
+// suppression applies: the function declaration documented by its comment
+// group, or else the innermost function enclosing it.
+func enclosingFunction(f *ast.File, commentGroup *ast.CommentGroup, pos token.Pos) ast.Node {
+	for _, decl := range f.Decls {
+		if fd, ok := decl.(*ast.FuncDecl); ok && fd.Doc == commentGroup {
+			return fd
+		}
+	}
+	path, _ := astutil.PathEnclosingInterval(f, pos, pos)
+	for _, n := range path {
+		switch n.(type) {
+		case *ast.FuncDecl, *ast.FuncLit:
+			return n
+		}
+	}
+	return nil
 }
 
-// isSuppressingCommentGroup determines whether a comment group contains
-// the suppression string at the beginning of a line in the comment text.
-func isSuppressingCommentGroup(commentGroup *ast.CommentGroup) bool {
-	for _, line := range strings.Split(commentGroup.Text(), "\n") {
-		trimmed := strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(line, "//"), "/*"))
-		if strings.HasPrefix(trimmed, suppressionString) {
+func contains(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
 			return true
 		}
 	}
@@ -15,11 +15,60 @@
 package suppression
 
 import (
+	"fmt"
+	"go/ast"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"golang.org/x/tools/go/analysis/analysistest"
 )
 
 func TestAnalyzer(t *testing.T) {
-	analysistest.Run(t, analysistest.TestData(), Analyzer, "./...")
+	results := analysistest.Run(t, analysistest.TestData(), Analyzer, "./...")
+	pass, result := results[0].Pass, results[0].Result.(ResultType)
+
+	var got []string
+	for _, s := range result.All {
+		got = append(got, fmt.Sprintf("%d: scope=%q reason=%q labels=%v err=%v", pass.Fset.Position(s.Pos).Line, s.Scope, s.Reason, s.Labels, s.Err))
+	}
+	want := []string{
+		`17: scope="file" reason="generated code" labels=[] err=<nil>`,
+		`20: scope="" reason="" labels=[] err=<nil>`,
+		`31: scope="" reason="" labels=[] err=<nil>`,
+		`35: scope="" reason="" labels=[] err=<nil>`,
+		`39: scope="" reason="the value is redacted" labels=[pii credentials] err=<nil>`,
+		`42: scope="" reason="verified" labels=[] err=<nil>`,
+		`44: scope="" reason="" labels=[] err=malformed suppression: expected = after reason`,
+		`48: scope="function" reason="" labels=[] err=<nil>`,
+		`55: scope="function" reason="" labels=[] err=<nil>`,
+		`60: scope="function" reason="" labels=[] err=a function-scoped suppression must document a function or be within one`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("suppressions diff (-want +got):\n%s", diff)
+	}
+
+	// Each valid suppression applies to a node, a function, or a file.
+	var suppressedCalls, suppressedFuncs int
+	for _, f := range pass.Files {
+		if len(result.FileSuppressions(f)) != 1 {
+			t.Errorf("got %d file suppressions, want 1", len(result.FileSuppressions(f)))
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch n.(type) {
+			case *ast.ExprStmt:
+				if result.IsSuppressed(n) {
+					suppressedCalls++
+				}
+			case *ast.FuncDecl, *ast.FuncLit:
+				suppressedFuncs += len(result.FunctionSuppressions(n))
+			}
+			return true
+		})
+	}
+	if suppressedCalls != 5 {
+		t.Errorf("got %d suppressed calls, want 5", suppressedCalls)
+	}
+	if suppressedFuncs != 2 {
+		t.Errorf("got %d suppressed functions, want 2", suppressedFuncs)
+	}
 }
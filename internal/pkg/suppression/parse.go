@@ -0,0 +1,118 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package suppression
+
+import (
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// parse parses the text following the suppression string in a suppression.
+// Unless the text begins with a parenthesis, it is free-form, e.g.
+//
+//	// levee.DoNotReport: this value is redacted
+//
+// Otherwise, it holds comma-separated arguments within parentheses:
+//
+//	// levee.DoNotReport(reason="this value is redacted", label=pii, scope=function)
+//
+// Values are identifiers or quoted strings. The label argument may be repeated.
+func parse(pos token.Pos, text string) *Suppression {
+	s := &Suppression{Pos: pos}
+	if !strings.HasPrefix(text, "(") {
+		return s
+	}
+	if err := parseArgs(s, text); err != nil {
+		s.Err = fmt.Errorf("malformed suppression: %v", err)
+	}
+	return s
+}
+
+// parseArgs parses a parenthesized argument list into a suppression.
+func parseArgs(s *Suppression, text string) error {
+	var (
+		sc      scanner.Scanner
+		scanErr error
+		seen    = make(map[string]bool)
+		src     = []byte(text)
+	)
+	file := token.NewFileSet().AddFile("", -1, len(src))
+	sc.Init(file, src, func(_ token.Position, msg string) { scanErr = fmt.Errorf("%s", msg) }, 0)
+	next := func() (token.Token, string) {
+		_, tok, lit := sc.Scan()
+		return tok, lit
+	}
+
+	// Skip the opening parenthesis.
+	next()
+	for {
+		tok, key := next()
+		if tok == token.RPAREN && len(seen) == 0 {
+			break
+		}
+		if tok != token.IDENT {
+			return fmt.Errorf("expected an argument name")
+		}
+		if tok, _ := next(); tok != token.ASSIGN {
+			return fmt.Errorf("expected = after %s", key)
+		}
+		tok, lit := next()
+		var value string
+		switch tok {
+		case token.IDENT:
+			value = lit
+		case token.STRING:
+			v, err := strconv.Unquote(lit)
+			if err != nil {
+				return fmt.Errorf("invalid value for %s: %v", key, err)
+			}
+			value = v
+		default:
+			return fmt.Errorf("expected an identifier or a quoted string as the value of %s", key)
+		}
+		if seen[key] && key != "label" {
+			return fmt.Errorf("%s is given more than once", key)
+		}
+		seen[key] = true
+
+		switch key {
+		case "reason":
+			s.Reason = value
+		case "label":
+			s.Labels = append(s.Labels, value)
+		case "scope":
+			switch sc := Scope(value); sc {
+			case FunctionScope, FileScope:
+				s.Scope = sc
+			default:
+				return fmt.Errorf("invalid scope %q: please provide one of function, file", value)
+			}
+		default:
+			return fmt.Errorf("unknown argument %s: expect one of reason, label, scope", key)
+		}
+
+		tok, _ = next()
+		if tok == token.RPAREN {
+			break
+		}
+		if tok != token.COMMA {
+			return fmt.Errorf("expected , or )")
+		}
+	}
+	return scanErr
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package suppression
+
+import (
+	"go/token"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		text    string
+		want    *Suppression
+		wantErr bool
+	}{
+		{
+			desc: "Free-form text is not parsed",
+			text: ": verified (by me)",
+			want: &Suppression{},
+		},
+		{
+			desc: "Empty arguments",
+			text: "()",
+			want: &Suppression{},
+		},
+		{
+			desc: "All arguments",
+			text: `(reason="the token is redacted", label=credentials, label="pii", scope=file)`,
+			want: &Suppression{Reason: "the token is redacted", Labels: []string{"credentials", "pii"}, Scope: FileScope},
+		},
+		{
+			desc: "Text following the arguments is ignored",
+			text: "(scope=function) // more text",
+			want: &Suppression{Scope: FunctionScope},
+		},
+		{
+			desc:    "Unknown arguments are rejected",
+			text:    "(severity=low)",
+			wantErr: true,
+		},
+		{
+			desc:    "Unknown scopes are rejected",
+			text:    "(scope=package)",
+			wantErr: true,
+		},
+		{
+			desc:    "Reasons may only be given once",
+			text:    `(reason="a", reason="b")`,
+			wantErr: true,
+		},
+		{
+			desc:    "Values must be identifiers or strings",
+			text:    "(reason=42)",
+			wantErr: true,
+		},
+		{
+			desc:    "Arguments must be separated by commas",
+			text:    "(reason=verified label=pii)",
+			wantErr: true,
+		},
+		{
+			desc:    "Arguments must be closed",
+			text:    "(reason=verified",
+			wantErr: true,
+		},
+		{
+			desc:    "Strings must be terminated",
+			text:    `(reason="verified)`,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := parse(token.NoPos, tc.text)
+
+			if (got.Err != nil) != tc.wantErr {
+				t.Fatalf("got err = %v, want error: %v", got.Err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tc.want, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("suppression diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -14,9 +14,11 @@
 
 package comments
 
+// levee.DoNotReport(scope=file, reason="generated code")
+
 func TestSuppressionComments() {
 	// levee.DoNotReport
-	println() // want "suppressed"
+	println()
 
 	println()
 
@@ -28,7 +30,33 @@ func TestSuppressionComments() {
 		This comment is suppressing too.
 		levee.DoNotReport
 	*/
-	println() // want "suppressed"
+	println()
+
+	println() // levee.DoNotReport
+}
+
+func TestStructuredSuppressions() {
+	// levee.DoNotReport(reason="the value is redacted", label=pii, label="credentials")
+	println()
+
+	println() // levee.DoNotReport(reason=verified)
 
-	println() // levee.DoNotReport // want "suppressed"
+	// levee.DoNotReport(reason verified)
+	println()
+}
+
+// levee.DoNotReport(scope=function)
+func TestFunctionSuppression() {
+	println()
 }
+
+func TestFunctionSuppressionWithinFunction() {
+	func() {
+		// levee.DoNotReport(scope=function)
+		println()
+	}()
+}
+
+// levee.DoNotReport(scope=function)
+
+var x = 0
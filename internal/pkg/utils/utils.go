@@ -82,6 +82,37 @@ func DecomposeFunction(f *ssa.Function) (path, recv, name string) {
 	return
 }
 
+// DecomposeCallee returns the path, receiver, and name strings of the
+// function called by a call. For "invoke" calls, these describe the
+// interface method being called. If the called function cannot be
+// determined statically, ok is false.
+func DecomposeCallee(c *ssa.CallCommon) (path, recv, name string, ok bool) {
+	if c.IsInvoke() {
+		if pkg := c.Method.Pkg(); pkg != nil {
+			path = pkg.Path()
+		}
+		if recvVar := c.Method.Type().(*types.Signature).Recv(); recvVar != nil {
+			recv = UnqualifiedName(recvVar)
+		}
+		return path, recv, c.Method.Name(), true
+	}
+	if sc := c.StaticCallee(); sc != nil {
+		path, recv, name = DecomposeFunction(sc)
+		return path, recv, name, true
+	}
+	return "", "", "", false
+}
+
+// CallArgs returns the arguments of a call. For "invoke" calls,
+// the receiver is included as the first argument.
+func CallArgs(c *ssa.CallCommon) []ssa.Value {
+	var args []ssa.Value
+	if c.IsInvoke() {
+		args = append(args, c.Value)
+	}
+	return append(args, c.Args...)
+}
+
 // EmptyInterfaceString is the string rendering of an empty interface, interface{}.
 // Changes based on the go version.
 var DefaultEmptyInterface = "interface{}"
@@ -16,17 +16,98 @@
 package levee
 
 import (
+	"fmt"
+
 	"github.com/google/go-flow-levee/internal/pkg/config"
 	"github.com/google/go-flow-levee/internal/pkg/levee"
 	"github.com/google/go-flow-levee/internal/pkg/propagation/summary"
+	"golang.org/x/tools/go/analysis"
 )
 
 // Analyzer reports instances of source data reaching a sink.
+// It reads its configuration from the file selected by the -config flag.
 var Analyzer = levee.Analyzer
 
+// NewAnalyzer returns an analyzer that reports instances of source data
+// reaching a sink, as configured by conf. The analyzer, and the analyzers
+// it requires, are independent of Analyzer and of each other: they do not
+// read the -config flag, and they have their own output flags.
+//
+// A single driver, e.g. a multichecker, may run the analyzer along with Analyzer.
+// To run analyzers bound to several configurations, use NewAnalyzers.
+func NewAnalyzer(conf *Config) (*analysis.Analyzer, error) {
+	if err := validate(conf); err != nil {
+		return nil, err
+	}
+	return levee.NewAnalyzer(conf), nil
+}
+
+// NewAnalyzers returns an analyzer bound to each configuration, as NewAnalyzer does.
+// A single driver may run the analyzers together, along with Analyzer.
+// To tell them apart, the analyzers are named after the order of their
+// configurations, e.g. levee1 and levee2.
+func NewAnalyzers(confs ...*Config) ([]*analysis.Analyzer, error) {
+	for _, conf := range confs {
+		if err := validate(conf); err != nil {
+			return nil, err
+		}
+	}
+	return levee.NewAnalyzers(confs...), nil
+}
+
+func validate(conf *Config) error {
+	if conf == nil {
+		return fmt.Errorf("levee: NewAnalyzer requires a configuration")
+	}
+	if err := conf.Validate(); err != nil {
+		return fmt.Errorf("levee: invalid configuration: %v", err)
+	}
+	return nil
+}
+
 // SetBytes is a wrapper around the config package's SetBytes function.
 var SetBytes = config.SetBytes
 
+// Config is a wrapper around the config package's Config type.
+// It holds the same settings as a configuration file,
+// with the field names used in configuration files.
+type Config = config.Config
+
+// The matchers of a Config are wrappers around the config package's matchers.
+// Their StringMatcher fields may be nil, in which case they match any name.
+type (
+	StringMatcher     = config.StringMatcher
+	SourceMatcher     = config.SourceMatcher
+	SourceFuncMatcher = config.SourceFuncMatcher
+	FuncMatcher       = config.FuncMatcher
+	SinkMatcher       = config.SinkMatcher
+	SanitizerMatcher  = config.SanitizerMatcher
+	ValidatorMatcher  = config.ValidatorMatcher
+	LabelMatcher      = config.LabelMatcher
+	FieldTagMatcher   = config.FieldTagMatcher
+	SummaryMatcher    = config.SummaryMatcher
+	FuncSummary       = config.FuncSummary
+	FixSanitizer      = config.FixSanitizer
+	Overlay           = config.Overlay
+	CallGraphType     = config.CallGraphType
+)
+
+// The call graphs that a Config may select.
+const (
+	StaticCallGraph = config.StaticCallGraph
+	CHACallGraph    = config.CHACallGraph
+	RTACallGraph    = config.RTACallGraph
+	VTACallGraph    = config.VTACallGraph
+)
+
+// Literal is a wrapper around the config package's Literal function,
+// which returns a StringMatcher matching a name exactly.
+var Literal = config.Literal
+
+// Regexp is a wrapper around the config package's Regexp function,
+// which returns a StringMatcher matching names with a regular expression.
+var Regexp = config.Regexp
+
 // Summary is a wrapper around the propagation/summary
 // package's Summary type.
 type Summary = summary.Summary